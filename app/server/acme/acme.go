@@ -0,0 +1,240 @@
+package acme
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/acme"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const userAgent = "caddy-delivery-network"
+
+type Config struct {
+	DirectoryURL string      // ACME 目录地址
+	Email        string      // 账户联系邮箱
+	EAB          *EAB        // 外部账户绑定，部分商业 CA 需要
+	TrustedRoots string      // 额外信任的根证书（ PEM ），用于对接使用自签名证书的测试 CA （例如 Pebble ）
+	Logger       *zap.Logger // 记录不影响签发结果的错误（例如清理验证内容失败），为空时不记录
+}
+
+type EAB struct {
+	KID     string // 密钥 ID
+	HMACKey string // base64url 编码的 HMAC 密钥
+}
+
+type Client struct {
+	cfg    *Config
+	client *acme.Client
+	l      *zap.Logger
+}
+
+// Certificate 签发得到的证书，均为 DER 编码
+type Certificate struct {
+	Leaf  *x509.Certificate
+	Chain [][]byte // 不含叶子证书的中间证书链
+	CSR   []byte
+}
+
+func New(cfg *Config, accountKey crypto.Signer, accountURL string) (*Client, error) {
+	if cfg.DirectoryURL == "" {
+		return nil, errors.New("directory url is empty")
+	}
+
+	httpClient := http.DefaultClient
+	if cfg.TrustedRoots != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(cfg.TrustedRoots)) {
+			return nil, errors.New("failed to parse trusted roots")
+		}
+		httpClient = &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{RootCAs: pool},
+			},
+		}
+	}
+
+	l := cfg.Logger
+	if l == nil {
+		l = zap.NewNop()
+	}
+
+	return &Client{
+		cfg: cfg,
+		l:   l,
+		client: &acme.Client{
+			Key:          accountKey,
+			KID:          acme.KeyID(accountURL), // 为空时会自动向 CA 查询
+			DirectoryURL: cfg.DirectoryURL,
+			HTTPClient:   httpClient,
+			UserAgent:    userAgent,
+		},
+	}, nil
+}
+
+// Register 注册账户，如果账户已存在则直接取回，返回账户地址
+func (c *Client) Register(ctx context.Context) (string, error) {
+	acct := &acme.Account{}
+	if c.cfg.Email != "" {
+		acct.Contact = []string{"mailto:" + c.cfg.Email}
+	}
+	if c.cfg.EAB != nil {
+		hmacKey, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(c.cfg.EAB.HMACKey, "="))
+		if err != nil {
+			return "", fmt.Errorf("decode eab hmac key: %w", err)
+		}
+		acct.ExternalAccountBinding = &acme.ExternalAccountBinding{
+			KID: c.cfg.EAB.KID,
+			Key: hmacKey,
+		}
+	}
+
+	registered, err := c.client.Register(ctx, acct, acme.AcceptTOS)
+	if errors.Is(err, acme.ErrAccountAlreadyExists) {
+		// 使用同一个密钥注册过了，取回即可
+		registered, err = c.client.GetReg(ctx, "")
+	}
+	if err != nil {
+		return "", fmt.Errorf("register account: %w", err)
+	}
+
+	return registered.URI, nil
+}
+
+// Obtain 完成下单、验证和签发的完整流程
+func (c *Client) Obtain(ctx context.Context, domains []string, certKey crypto.Signer, solvers []Solver) (*Certificate, error) {
+	if len(domains) == 0 {
+		return nil, errors.New("no domains")
+	}
+
+	// 下单
+	order, err := c.client.AuthorizeOrder(ctx, acme.DomainIDs(domains...))
+	if err != nil {
+		return nil, fmt.Errorf("authorize order: %w", err)
+	}
+
+	// 逐个完成验证
+	for _, authzURL := range order.AuthzURLs {
+		if err := c.authorize(ctx, authzURL, solvers); err != nil {
+			return nil, err
+		}
+	}
+
+	// 等待订单就绪
+	if order, err = c.client.WaitOrder(ctx, order.URI); err != nil {
+		return nil, fmt.Errorf("wait order: %w", err)
+	}
+
+	// 生成签发请求并完成订单
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: domains[0]},
+		DNSNames: domains,
+	}, certKey)
+	if err != nil {
+		return nil, fmt.Errorf("create csr: %w", err)
+	}
+
+	ders, _, err := c.client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return nil, fmt.Errorf("finalize order: %w", err)
+	}
+	if len(ders) == 0 {
+		return nil, errors.New("empty certificate chain")
+	}
+
+	leaf, err := x509.ParseCertificate(ders[0])
+	if err != nil {
+		return nil, fmt.Errorf("parse issued certificate: %w", err)
+	}
+
+	return &Certificate{
+		Leaf:  leaf,
+		Chain: ders[1:],
+		CSR:   csr,
+	}, nil
+}
+
+func (c *Client) authorize(ctx context.Context, authzURL string, solvers []Solver) error {
+	authz, err := c.client.GetAuthorization(ctx, authzURL)
+	if err != nil {
+		return fmt.Errorf("get authorization: %w", err)
+	}
+	if authz.Status == acme.StatusValid {
+		// 之前已经验证过了
+		return nil
+	}
+
+	// 选择第一个能处理的验证方式（按 solvers 的顺序优先）
+	var (
+		chal   *acme.Challenge
+		solver Solver
+	)
+	for _, s := range solvers {
+		for _, ch := range authz.Challenges {
+			if ch.Type == s.Type() {
+				chal, solver = ch, s
+				break
+			}
+		}
+		if chal != nil {
+			break
+		}
+	}
+	if chal == nil {
+		return fmt.Errorf("no supported challenge for %s", authz.Identifier.Value)
+	}
+
+	// 准备验证内容
+	ch := &Challenge{
+		Type:   chal.Type,
+		Domain: authz.Identifier.Value,
+		Token:  chal.Token,
+	}
+	switch chal.Type {
+	case ChallengeTypeHTTP01:
+		ch.KeyAuth, err = c.client.HTTP01ChallengeResponse(chal.Token)
+	case ChallengeTypeDNS01:
+		ch.KeyAuth, err = c.client.DNS01ChallengeRecord(chal.Token)
+	default:
+		err = fmt.Errorf("unsupported challenge type %s", chal.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("prepare challenge: %w", err)
+	}
+
+	// 部署，并确保结束后清理
+	if err = solver.Present(ctx, ch); err != nil {
+		return fmt.Errorf("present %s challenge for %s: %w", ch.Type, ch.Domain, err)
+	}
+	defer func() {
+		// 使用独立的 context ，避免主流程超时导致无法清理
+		cleanCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := solver.CleanUp(cleanCtx, ch); err != nil {
+			c.l.Error("failed to clean up challenge", zap.String("type", ch.Type), zap.String("domain", ch.Domain), zap.Error(err))
+		}
+	}()
+
+	// 通知 CA 开始验证，并等待结果
+	if _, err = c.client.Accept(ctx, chal); err != nil {
+		return fmt.Errorf("accept challenge: %w", err)
+	}
+	if _, err = c.client.WaitAuthorization(ctx, authz.URI); err != nil {
+		return fmt.Errorf("wait authorization for %s: %w", ch.Domain, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,515 @@
+package acme
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"golang.org/x/crypto/acme"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeCA 一个最小化的 RFC 8555 服务端，只实现签发流程需要的接口，不校验 JWS 签名
+type fakeCA struct {
+	server *httptest.Server
+
+	caKey  crypto.Signer
+	caCert *x509.Certificate
+
+	mu        sync.Mutex
+	accounts  map[string]string // jwk -> 账户地址
+	orders    map[string]*fakeOrder
+	authzs    map[string]*fakeAuthz
+	nextID    int
+	keyAuthOf func(token string) string // 验证时读取 solver 部署的内容
+	accepted  []string                  // 收到验证请求时 solver 部署的内容
+}
+
+type fakeOrder struct {
+	Status         string         `json:"status"`
+	Identifiers    []acme.AuthzID `json:"identifiers"`
+	Authorizations []string       `json:"authorizations"`
+	Finalize       string         `json:"finalize"`
+	Certificate    string         `json:"certificate,omitempty"`
+	url            string         `json:"-"`
+	chain          []byte         `json:"-"`
+	authzs         []*fakeAuthz   `json:"-"`
+}
+
+type fakeAuthz struct {
+	Status     string          `json:"status"`
+	Identifier acme.AuthzID    `json:"identifier"`
+	Challenges []fakeChallenge `json:"challenges"`
+}
+
+type fakeChallenge struct {
+	Type   string `json:"type"`
+	URL    string `json:"url"`
+	Token  string `json:"token"`
+	Status string `json:"status"`
+}
+
+func newFakeCA(t *testing.T) *fakeCA {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fake acme ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, caKey.Public(), caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ca := &fakeCA{
+		caKey:    caKey,
+		caCert:   caCert,
+		accounts: make(map[string]string),
+		orders:   make(map[string]*fakeOrder),
+		authzs:   make(map[string]*fakeAuthz),
+	}
+	ca.server = httptest.NewServer(http.HandlerFunc(ca.serve))
+	t.Cleanup(ca.server.Close)
+	return ca
+}
+
+func (ca *fakeCA) url(path string) string {
+	return ca.server.URL + path
+}
+
+func (ca *fakeCA) newID() string {
+	ca.nextID++
+	return fmt.Sprintf("%d", ca.nextID)
+}
+
+func (ca *fakeCA) serve(w http.ResponseWriter, r *http.Request) {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+
+	w.Header().Set("Replay-Nonce", fmt.Sprintf("nonce-%d", time.Now().UnixNano()))
+
+	if r.URL.Path == "/directory" {
+		writeJSON(w, http.StatusOK, map[string]string{
+			"newNonce":   ca.url("/new-nonce"),
+			"newAccount": ca.url("/new-account"),
+			"newOrder":   ca.url("/new-order"),
+		})
+		return
+	}
+	if r.URL.Path == "/new-nonce" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// 其余的请求都是 JWS
+	var jws struct {
+		Protected string `json:"protected"`
+		Payload   string `json:"payload"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&jws); err != nil {
+		writeProblem(w, http.StatusBadRequest, "malformed", err.Error())
+		return
+	}
+	protectedBytes, _ := base64.RawURLEncoding.DecodeString(jws.Protected)
+	var protected struct {
+		JWK json.RawMessage `json:"jwk"`
+	}
+	_ = json.Unmarshal(protectedBytes, &protected)
+	payload, _ := base64.RawURLEncoding.DecodeString(jws.Payload)
+
+	switch {
+	case r.URL.Path == "/new-account":
+		ca.newAccount(w, string(protected.JWK), payload)
+	case r.URL.Path == "/new-order":
+		ca.newOrder(w, payload)
+	case strings.HasPrefix(r.URL.Path, "/authz/"):
+		ca.getAuthz(w, r.URL.Path)
+	case strings.HasPrefix(r.URL.Path, "/chal/"):
+		ca.acceptChallenge(w, r.URL.Path)
+	case strings.HasPrefix(r.URL.Path, "/order/"):
+		ca.getOrder(w, r.URL.Path)
+	case strings.HasPrefix(r.URL.Path, "/finalize/"):
+		ca.finalize(w, r.URL.Path, payload)
+	case strings.HasPrefix(r.URL.Path, "/cert/"):
+		order := ca.orders[strings.TrimPrefix(r.URL.Path, "/cert/")]
+		w.Header().Set("Content-Type", "application/pem-certificate-chain")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(order.chain)
+	default:
+		writeProblem(w, http.StatusNotFound, "malformed", "not found")
+	}
+}
+
+func (ca *fakeCA) newAccount(w http.ResponseWriter, jwk string, payload []byte) {
+	var req struct {
+		OnlyReturnExisting bool `json:"onlyReturnExisting"`
+	}
+	_ = json.Unmarshal(payload, &req)
+
+	if accountURL, ok := ca.accounts[jwk]; ok {
+		w.Header().Set("Location", accountURL)
+		writeJSON(w, http.StatusOK, map[string]string{"status": "valid"})
+		return
+	}
+	if req.OnlyReturnExisting {
+		writeProblem(w, http.StatusBadRequest, "accountDoesNotExist", "no such account")
+		return
+	}
+
+	accountURL := ca.url("/account/" + ca.newID())
+	ca.accounts[jwk] = accountURL
+	w.Header().Set("Location", accountURL)
+	writeJSON(w, http.StatusCreated, map[string]string{"status": "valid"})
+}
+
+func (ca *fakeCA) newOrder(w http.ResponseWriter, payload []byte) {
+	var req struct {
+		Identifiers []acme.AuthzID `json:"identifiers"`
+	}
+	if err := json.Unmarshal(payload, &req); err != nil {
+		writeProblem(w, http.StatusBadRequest, "malformed", err.Error())
+		return
+	}
+
+	id := ca.newID()
+	order := &fakeOrder{
+		Status:      acme.StatusPending,
+		Identifiers: req.Identifiers,
+		Finalize:    ca.url("/finalize/" + id),
+		url:         ca.url("/order/" + id),
+	}
+	for _, identifier := range req.Identifiers {
+		authzID := ca.newID()
+		authz := &fakeAuthz{
+			Status:     acme.StatusPending,
+			Identifier: identifier,
+			Challenges: []fakeChallenge{
+				{Type: ChallengeTypeHTTP01, URL: ca.url("/chal/" + authzID + "/http"), Token: "token-http-" + authzID, Status: acme.StatusPending},
+				{Type: ChallengeTypeDNS01, URL: ca.url("/chal/" + authzID + "/dns"), Token: "token-dns-" + authzID, Status: acme.StatusPending},
+			},
+		}
+		ca.authzs[authzID] = authz
+		order.authzs = append(order.authzs, authz)
+		order.Authorizations = append(order.Authorizations, ca.url("/authz/"+authzID))
+	}
+	ca.orders[id] = order
+
+	w.Header().Set("Location", order.url)
+	writeJSON(w, http.StatusCreated, order)
+}
+
+func (ca *fakeCA) getAuthz(w http.ResponseWriter, path string) {
+	authz, ok := ca.authzs[strings.TrimPrefix(path, "/authz/")]
+	if !ok {
+		writeProblem(w, http.StatusNotFound, "malformed", "no such authz")
+		return
+	}
+	writeJSON(w, http.StatusOK, authz)
+}
+
+func (ca *fakeCA) acceptChallenge(w http.ResponseWriter, path string) {
+	authzID, _, _ := strings.Cut(strings.TrimPrefix(path, "/chal/"), "/")
+	authz := ca.authzs[authzID]
+	for i := range authz.Challenges {
+		chal := &authz.Challenges[i]
+		if chal.URL != ca.url(path) {
+			continue
+		}
+
+		// 接受验证时 solver 应该已经部署好了
+		presented := ca.keyAuthOf(chal.Token)
+		ca.accepted = append(ca.accepted, presented)
+		if presented == "" {
+			chal.Status, authz.Status = acme.StatusInvalid, acme.StatusInvalid
+		} else {
+			chal.Status, authz.Status = acme.StatusValid, acme.StatusValid
+		}
+		writeJSON(w, http.StatusOK, chal)
+		return
+	}
+	writeProblem(w, http.StatusNotFound, "malformed", "no such challenge")
+}
+
+func (ca *fakeCA) getOrder(w http.ResponseWriter, path string) {
+	order := ca.orders[strings.TrimPrefix(path, "/order/")]
+	if order.Status == acme.StatusPending {
+		ready := true
+		for _, authz := range order.authzs {
+			ready = ready && authz.Status == acme.StatusValid
+		}
+		if ready {
+			order.Status = acme.StatusReady
+		}
+	}
+	w.Header().Set("Location", order.url)
+	writeJSON(w, http.StatusOK, order)
+}
+
+func (ca *fakeCA) finalize(w http.ResponseWriter, path string, payload []byte) {
+	id := strings.TrimPrefix(path, "/finalize/")
+	order := ca.orders[id]
+
+	var req struct {
+		CSR string `json:"csr"`
+	}
+	_ = json.Unmarshal(payload, &req)
+	csrDER, _ := base64.RawURLEncoding.DecodeString(req.CSR)
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	if err != nil {
+		writeProblem(w, http.StatusBadRequest, "badCSR", err.Error())
+		return
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(int64(ca.nextID) + 100),
+		Subject:      pkix.Name{CommonName: csr.Subject.CommonName},
+		DNSNames:     csr.DNSNames,
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.caCert, csr.PublicKey, ca.caKey)
+	if err != nil {
+		writeProblem(w, http.StatusInternalServerError, "serverInternal", err.Error())
+		return
+	}
+	order.chain = append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.caCert.Raw})...)
+	order.Status = acme.StatusValid
+	order.Certificate = ca.url("/cert/" + id)
+
+	w.Header().Set("Location", order.url)
+	writeJSON(w, http.StatusOK, order)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeProblem(w http.ResponseWriter, status int, problemType string, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"type":   "urn:ietf:params:acme:error:" + problemType,
+		"detail": detail,
+	})
+}
+
+// memorySolver 把验证内容保存在内存里，代替真正的部署
+type memorySolver struct {
+	challengeType string
+
+	mu       sync.Mutex
+	presents map[string]*Challenge
+	cleaned  []string
+}
+
+func newMemorySolver(challengeType string) *memorySolver {
+	return &memorySolver{challengeType: challengeType, presents: make(map[string]*Challenge)}
+}
+
+func (s *memorySolver) Type() string {
+	return s.challengeType
+}
+
+func (s *memorySolver) Present(_ context.Context, ch *Challenge) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.presents[ch.Token] = ch
+	return nil
+}
+
+func (s *memorySolver) CleanUp(_ context.Context, ch *Challenge) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.presents, ch.Token)
+	s.cleaned = append(s.cleaned, ch.Domain)
+	return nil
+}
+
+func (s *memorySolver) keyAuth(token string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ch, ok := s.presents[token]; ok {
+		return ch.KeyAuth
+	}
+	return ""
+}
+
+func newTestKey(t *testing.T) crypto.Signer {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestRegister(t *testing.T) {
+	ca := newFakeCA(t)
+	accountKey := newTestKey(t)
+
+	client, err := New(&Config{DirectoryURL: ca.url("/directory"), Email: "admin@example.com"}, accountKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountURL, err := client.Register(context.Background())
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if !strings.HasPrefix(accountURL, ca.url("/account/")) {
+		t.Fatalf("unexpected account url %q", accountURL)
+	}
+
+	// 同一个密钥再次注册时取回已有的账户
+	client, err = New(&Config{DirectoryURL: ca.url("/directory")}, accountKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := client.Register(context.Background())
+	if err != nil {
+		t.Fatalf("register again: %v", err)
+	}
+	if again != accountURL {
+		t.Fatalf("expected existing account %q, got %q", accountURL, again)
+	}
+}
+
+func TestObtain(t *testing.T) {
+	tests := []struct {
+		name          string
+		solvers       []string
+		wantChallenge string
+	}{
+		{"http-01", []string{ChallengeTypeHTTP01}, ChallengeTypeHTTP01},
+		{"dns-01", []string{ChallengeTypeDNS01}, ChallengeTypeDNS01},
+		{"solver order decides", []string{ChallengeTypeDNS01, ChallengeTypeHTTP01}, ChallengeTypeDNS01},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ca := newFakeCA(t)
+			accountKey := newTestKey(t)
+
+			var solvers []Solver
+			byType := make(map[string]*memorySolver)
+			for _, challengeType := range tt.solvers {
+				s := newMemorySolver(challengeType)
+				solvers = append(solvers, s)
+				byType[challengeType] = s
+			}
+			ca.keyAuthOf = byType[tt.wantChallenge].keyAuth
+
+			client, err := New(&Config{DirectoryURL: ca.url("/directory")}, accountKey, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := client.Register(context.Background()); err != nil {
+				t.Fatalf("register: %v", err)
+			}
+
+			domains := []string{"example.com", "www.example.com"}
+			certKey := newTestKey(t)
+			cert, err := client.Obtain(context.Background(), domains, certKey, solvers)
+			if err != nil {
+				t.Fatalf("obtain: %v", err)
+			}
+
+			// 证书内容
+			if got := strings.Join(cert.Leaf.DNSNames, ","); got != strings.Join(domains, ",") {
+				t.Errorf("unexpected dns names %q", got)
+			}
+			if !cert.Leaf.PublicKey.(*ecdsa.PublicKey).Equal(certKey.Public()) {
+				t.Error("issued certificate does not use the cert key")
+			}
+			if len(cert.Chain) != 1 {
+				t.Errorf("expected 1 intermediate, got %d", len(cert.Chain))
+			}
+			if csr, err := x509.ParseCertificateRequest(cert.CSR); err != nil || csr.Subject.CommonName != domains[0] {
+				t.Errorf("unexpected csr: %v", err)
+			}
+
+			// 每个域名都应该按照期望的方式部署过正确的验证内容，并且已经清理
+			thumbprint, err := acme.JWKThumbprint(accountKey.Public())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ca.accepted) != len(domains) {
+				t.Fatalf("expected %d accepted challenges, got %d", len(domains), len(ca.accepted))
+			}
+			for _, keyAuth := range ca.accepted {
+				token, gotThumbprint, _ := strings.Cut(keyAuth, ".")
+				if tt.wantChallenge == ChallengeTypeDNS01 {
+					// DNS-01 部署的是 key authorization 的摘要，不包含 token
+					if strings.Contains(keyAuth, ".") || keyAuth == "" {
+						t.Errorf("unexpected dns-01 record value %q", keyAuth)
+					}
+					continue
+				}
+				if !strings.HasPrefix(token, "token-http-") || gotThumbprint != thumbprint {
+					t.Errorf("unexpected http-01 key authorization %q", keyAuth)
+				}
+			}
+			if got := len(byType[tt.wantChallenge].cleaned); got != len(domains) {
+				t.Errorf("expected %d clean ups, got %d", len(domains), got)
+			}
+		})
+	}
+}
+
+func TestObtainNoSupportedChallenge(t *testing.T) {
+	ca := newFakeCA(t)
+	ca.keyAuthOf = func(string) string { return "" }
+
+	client, err := New(&Config{DirectoryURL: ca.url("/directory")}, newTestKey(t), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Register(context.Background()); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	_, err = client.Obtain(context.Background(), []string{"example.com"}, newTestKey(t), []Solver{newMemorySolver("tls-alpn-01")})
+	if err == nil || !strings.Contains(err.Error(), "no supported challenge") {
+		t.Fatalf("expected no supported challenge error, got %v", err)
+	}
+}
+
+func TestDNS01RecordName(t *testing.T) {
+	tests := map[string]string{
+		"example.com":      "_acme-challenge.example.com.",
+		"example.com.":     "_acme-challenge.example.com.",
+		"www.example.com.": "_acme-challenge.www.example.com.",
+	}
+	for domain, want := range tests {
+		if got := dns01RecordName(domain); got != want {
+			t.Errorf("dns01RecordName(%q) = %q, want %q", domain, got, want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package acme
+
+import (
+	"context"
+	"strings"
+)
+
+const (
+	ChallengeTypeHTTP01 = "http-01"
+	ChallengeTypeDNS01  = "dns-01"
+)
+
+// Challenge 需要被 Solver 部署的验证信息
+type Challenge struct {
+	Type    string // 验证类型
+	Domain  string // 需要验证的域名（通配符域名会去掉 *. 前缀）
+	Token   string // CA 下发的 token
+	KeyAuth string // 需要部署的内容： HTTP-01 为响应体， DNS-01 为 TXT 记录值
+}
+
+type Solver interface {
+	Type() string
+	Present(ctx context.Context, ch *Challenge) error
+	CleanUp(ctx context.Context, ch *Challenge) error
+}
+
+// DNSProvider 负责管理 DNS-01 验证使用的 TXT 记录
+type DNSProvider interface {
+	Present(ctx context.Context, fqdn string, value string) error
+	CleanUp(ctx context.Context, fqdn string, value string) error
+	WaitPropagation(ctx context.Context, fqdn string, value string) error
+}
+
+// DNS01Solver 在 _acme-challenge 子域名下创建 TXT 记录，并等待其生效
+type DNS01Solver struct {
+	provider DNSProvider
+}
+
+var _ Solver = (*DNS01Solver)(nil)
+
+func NewDNS01Solver(provider DNSProvider) *DNS01Solver {
+	return &DNS01Solver{provider: provider}
+}
+
+func (s *DNS01Solver) Type() string {
+	return ChallengeTypeDNS01
+}
+
+func (s *DNS01Solver) Present(ctx context.Context, ch *Challenge) error {
+	fqdn := dns01RecordName(ch.Domain)
+	if err := s.provider.Present(ctx, fqdn, ch.KeyAuth); err != nil {
+		return err
+	}
+
+	return s.provider.WaitPropagation(ctx, fqdn, ch.KeyAuth)
+}
+
+func (s *DNS01Solver) CleanUp(ctx context.Context, ch *Challenge) error {
+	return s.provider.CleanUp(ctx, dns01RecordName(ch.Domain), ch.KeyAuth)
+}
+
+func dns01RecordName(domain string) string {
+	return "_acme-challenge." + strings.TrimSuffix(domain, ".") + "."
+}
@@ -0,0 +1,111 @@
+package certutil
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+)
+
+// 签发时回拨开始时间，容忍实例之间的时钟误差
+const backdate = 5 * time.Minute
+
+func serialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+func subjectKeyID(pub crypto.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha1.Sum(der)
+	return sum[:], nil
+}
+
+// CreateCA 创建 CA 证书， parent 为空时创建自签名的根证书，否则由 parent 签发中间证书
+func CreateCA(commonName string, key crypto.Signer, validity time.Duration, parent *x509.Certificate, parentKey crypto.Signer) ([]byte, error) {
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	skid, err := subjectKeyID(key.Public())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal public key: %w", err)
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-backdate),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		SubjectKeyId:          skid,
+	}
+
+	if parent == nil {
+		parent, parentKey = template, key
+	} else {
+		if !parent.IsCA {
+			return nil, errors.New("parent is not a ca")
+		}
+		// 中间证书只用来签发叶子证书
+		template.MaxPathLenZero = true
+		if template.NotAfter.After(parent.NotAfter) {
+			template.NotAfter = parent.NotAfter
+		}
+	}
+
+	return x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+}
+
+// IssueLeaf 由 CA 签发服务器证书，有效期不会超过 CA 本身
+func IssueLeaf(domains []string, key crypto.Signer, validity time.Duration, ca *x509.Certificate, caKey crypto.Signer) ([]byte, error) {
+	if len(domains) == 0 {
+		return nil, errors.New("no domain")
+	}
+	if !ca.IsCA {
+		return nil, errors.New("issuer is not a ca")
+	}
+
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: domains[0]},
+		NotBefore:    now.Add(-backdate),
+		NotAfter:     now.Add(validity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if _, ok := key.Public().(*rsa.PublicKey); ok {
+		// RSA 密钥交换需要 keyEncipherment ，否则严格的客户端会拒绝握手
+		template.KeyUsage |= x509.KeyUsageKeyEncipherment
+	}
+	if template.NotAfter.After(ca.NotAfter) {
+		template.NotAfter = ca.NotAfter
+	}
+	for _, domain := range domains {
+		if ip := net.ParseIP(domain); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, domain)
+		}
+	}
+
+	return x509.CreateCertificate(rand.Reader, template, ca, key.Public(), caKey)
+}
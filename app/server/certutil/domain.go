@@ -0,0 +1,31 @@
+package certutil
+
+import "strings"
+
+func normalizeDomain(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
+
+// MatchDomain 检查证书的域名列表能否覆盖这个主机名，通配符只匹配一级子域名
+func MatchDomain(certDomains []string, host string) bool {
+	host = normalizeDomain(host)
+	if host == "" {
+		return false
+	}
+
+	for _, pattern := range certDomains {
+		pattern = normalizeDomain(pattern)
+		if pattern == host {
+			return true
+		}
+
+		// *.example.com 覆盖 a.example.com ，不覆盖 example.com 和 a.b.example.com
+		if strings.HasPrefix(pattern, "*.") && !strings.HasPrefix(host, "*.") {
+			if i := strings.IndexByte(host, '.'); i > 0 && host[i+1:] == pattern[2:] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
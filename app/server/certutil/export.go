@@ -0,0 +1,73 @@
+package certutil
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+type File struct {
+	Name    string
+	Content []byte
+}
+
+// EncodePKCS12 生成带密码的 PKCS#12 包，使用现代算法（ AES-256 + PBKDF2 ）
+func EncodePKCS12(certPEM string, keyPEM string, chainPEM string, password string) ([]byte, error) {
+	certs, err := ParseCertificatesPEM([]byte(certPEM))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	key, err := ParsePrivateKeyPEM([]byte(keyPEM))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	var chain = certs[1:]
+	if chainPEM != "" {
+		intermediates, err := ParseCertificatesPEM([]byte(chainPEM))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse intermediate certificate: %w", err)
+		}
+		chain = append(chain, intermediates...)
+	}
+
+	return pkcs12.Modern.Encode(key, certs[0], chain, password)
+}
+
+// EncodeZip 把多个文件打包成 zip
+func EncodeZip(files []File) ([]byte, error) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, f := range files {
+		fw, err := w.Create(f.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", f.Name, err)
+		}
+		if _, err = fw.Write(f.Content); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", f.Name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close zip: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// JoinPEM 拼接多段 PEM ，保证每段之间有换行
+func JoinPEM(parts ...string) string {
+	var buf bytes.Buffer
+	for _, p := range parts {
+		if p == "" {
+			continue
+		}
+		buf.WriteString(p)
+		if p[len(p)-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,124 @@
+package certutil
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+type Bundle struct {
+	Leaf  *x509.Certificate
+	Chain []*x509.Certificate // 从签发叶子证书的中间证书开始，不包含自签名的根证书
+	Key   crypto.Signer
+}
+
+// ParseBundle 解析 PKCS#12 (PFX) 或者混合了证书、中间证书与私钥的 PEM 文件
+func ParseBundle(data []byte, password string) (*Bundle, error) {
+	var (
+		certs []*x509.Certificate
+		key   crypto.Signer
+	)
+
+	if bytes.Contains(data, []byte("-----BEGIN ")) {
+		blocks, err := DecodePEMBlocks(data)
+		if err != nil {
+			return nil, err
+		}
+		for i, block := range blocks {
+			switch block.Type {
+			case "CERTIFICATE":
+				cert, err := x509.ParseCertificate(block.Bytes)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse certificate in pem block %d: %w", i, err)
+				}
+				certs = append(certs, cert)
+			case "PRIVATE KEY", "RSA PRIVATE KEY", "EC PRIVATE KEY":
+				if key != nil {
+					return nil, errors.New("found more than 1 private key")
+				}
+				if key, err = ParsePrivateKeyDER(block.Bytes); err != nil {
+					return nil, fmt.Errorf("failed to parse private key in pem block %d: %w", i, err)
+				}
+			case "EC PARAMETERS":
+			case "ENCRYPTED PRIVATE KEY":
+				return nil, errors.New("encrypted private key is not supported, please decrypt it first")
+			default:
+				return nil, fmt.Errorf("unexpected pem block type %s", block.Type)
+			}
+		}
+	} else {
+		rawKey, leaf, chain, err := pkcs12.DecodeChain(data, password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode pkcs12: %w", err)
+		}
+		var ok bool
+		if key, ok = rawKey.(crypto.Signer); !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", rawKey)
+		}
+		certs = append([]*x509.Certificate{leaf}, chain...)
+	}
+
+	if key == nil {
+		return nil, errors.New("no private key found")
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificate found")
+	}
+
+	return OrderChain(certs, key)
+}
+
+// OrderChain 找出与私钥匹配的叶子证书，再逐级找到签发者排列证书链
+func OrderChain(certs []*x509.Certificate, key crypto.Signer) (*Bundle, error) {
+	pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+
+	bundle := Bundle{Key: key}
+	used := make([]bool, len(certs))
+	for i, cert := range certs {
+		if pub.Equal(cert.PublicKey) {
+			bundle.Leaf = cert
+			used[i] = true
+			break
+		}
+	}
+	if bundle.Leaf == nil {
+		return nil, errors.New("no certificate matches the private key")
+	}
+
+	for child := bundle.Leaf; ; {
+		found := -1
+		for i, cert := range certs {
+			if !used[i] && bytes.Equal(child.RawIssuer, cert.RawSubject) && child.CheckSignatureFrom(cert) == nil {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			break
+		}
+		used[found] = true
+
+		// 根证书由客户端自己信任，不需要下发
+		parent := certs[found]
+		if bytes.Equal(parent.RawIssuer, parent.RawSubject) && parent.CheckSignatureFrom(parent) == nil {
+			break
+		}
+		bundle.Chain = append(bundle.Chain, parent)
+		child = parent
+	}
+
+	// 与这张证书无关的内容很可能是打包错误
+	for i, cert := range certs {
+		if !used[i] && !bytes.Equal(cert.RawIssuer, cert.RawSubject) {
+			return nil, fmt.Errorf("certificate %q is not part of the chain", cert.Subject.CommonName)
+		}
+	}
+
+	return &bundle, nil
+}
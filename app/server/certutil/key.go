@@ -0,0 +1,126 @@
+package certutil
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+)
+
+type KeyType string
+
+const (
+	KeyTypeEC256   KeyType = "ec256"
+	KeyTypeEC384   KeyType = "ec384"
+	KeyTypeRSA2048 KeyType = "rsa2048"
+	KeyTypeRSA4096 KeyType = "rsa4096"
+)
+
+// DefaultKeyType 未指定密钥类型时使用
+const DefaultKeyType = KeyTypeEC256
+
+func GenerateKey(keyType KeyType) (crypto.Signer, error) {
+	switch keyType {
+	case "", KeyTypeEC256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case KeyTypeEC384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case KeyTypeRSA2048:
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case KeyTypeRSA4096:
+		return rsa.GenerateKey(rand.Reader, 4096)
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", keyType)
+	}
+}
+
+func EncodePrivateKeyPEM(key crypto.Signer) ([]byte, error) {
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		der, err := x509.MarshalECPrivateKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("marshal ec private key: %w", err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+	case *rsa.PrivateKey:
+		return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)}), nil
+	default:
+		// 其他类型统一使用 PKCS#8
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("marshal pkcs8 private key: %w", err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+	}
+}
+
+func ParsePrivateKeyPEM(keyPEM []byte) (crypto.Signer, error) {
+	// 跳过 openssl 生成的 EC PARAMETERS
+	block, rest := pem.Decode(keyPEM)
+	for block != nil && block.Type == "EC PARAMETERS" {
+		block, rest = pem.Decode(rest)
+	}
+	if block == nil {
+		return nil, errors.New("no pem block found")
+	}
+
+	return ParsePrivateKeyDER(block.Bytes)
+}
+
+func ParsePrivateKeyDER(der []byte) (crypto.Signer, error) {
+	// 依次尝试常见的编码格式
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+
+	return nil, errors.New("failed to parse private key")
+}
+
+func EncodeCertificatesPEM(ders ...[]byte) string {
+	var certPEM []byte
+	for _, der := range ders {
+		certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	return string(certPEM)
+}
+
+func EncodeCSRPEM(der []byte) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+}
+
+// CreateCSR 为域名生成签发请求，IP 地址会放到 IP SAN 中
+func CreateCSR(domains []string, key crypto.Signer, subject pkix.Name) ([]byte, error) {
+	if len(domains) == 0 {
+		return nil, errors.New("no domain")
+	}
+
+	if subject.CommonName == "" {
+		subject.CommonName = domains[0]
+	}
+	template := &x509.CertificateRequest{Subject: subject}
+	for _, domain := range domains {
+		if ip := net.ParseIP(domain); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, domain)
+		}
+	}
+
+	return x509.CreateCertificateRequest(rand.Reader, template, key)
+}
@@ -0,0 +1,202 @@
+package certutil
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	ValidationInvalidPEM         = "invalid_pem"
+	ValidationMultiplePEMBlocks  = "multiple_pem_blocks"
+	ValidationMissingPrivateKey  = "missing_private_key"
+	ValidationKeyMismatch        = "key_mismatch"
+	ValidationChainBroken        = "chain_broken"
+	ValidationExpired            = "expired"
+	ValidationNotYetValid        = "not_yet_valid"
+	ValidationUnsupportedKeyType = "unsupported_key_type"
+	ValidationInvalidBundle      = "invalid_bundle"
+	ValidationNotCA              = "not_ca"
+	ValidationDomainMismatch     = "domain_mismatch"
+)
+
+type ValidationError struct {
+	Field   string // 出错的字段
+	Code    string // 错误类型
+	Message string // 详细信息
+}
+
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	var messages []string
+	for _, v := range e {
+		messages = append(messages, fmt.Sprintf("%s: %s", v.Field, v.Message))
+	}
+	return strings.Join(messages, "; ")
+}
+
+// DecodePEMBlocks 解析所有 PEM 块，如果存在无法解析的内容则返回错误
+func DecodePEMBlocks(data []byte) ([]*pem.Block, error) {
+	var blocks []*pem.Block
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		blocks = append(blocks, block)
+	}
+
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return nil, fmt.Errorf("unexpected data after %d pem blocks", len(blocks))
+	}
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("no pem block found")
+	}
+
+	return blocks, nil
+}
+
+func ParseCertificatesPEM(data []byte) ([]*x509.Certificate, error) {
+	blocks, err := DecodePEMBlocks(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []*x509.Certificate
+	for i, block := range blocks {
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("pem block %d is %s, not CERTIFICATE", i, block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate %d: %w", i, err)
+		}
+		certs = append(certs, cert)
+	}
+
+	return certs, nil
+}
+
+// CheckKeyType 检查 Caddy 能否使用这种类型的密钥
+func CheckKeyType(pub crypto.PublicKey) error {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		if k.N.BitLen() < 2048 {
+			return fmt.Errorf("rsa key of %d bits is too weak", k.N.BitLen())
+		}
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256(), elliptic.P384(), elliptic.P521():
+		default:
+			return fmt.Errorf("unsupported ecdsa curve %s", k.Curve.Params().Name)
+		}
+	case ed25519.PublicKey:
+	default:
+		return fmt.Errorf("unsupported key type %T", pub)
+	}
+
+	return nil
+}
+
+// Validate 检查证书、私钥与中间证书链的一致性，任意部分为空时跳过相关的检查
+func Validate(certificate string, privateKey string, intermediate string, now time.Time) ValidationErrors {
+	var errs ValidationErrors
+
+	// 证书只能有一张，中间证书需要放到中间证书字段里
+	var leaf *x509.Certificate
+	if certificate != "" {
+		if certs, err := ParseCertificatesPEM([]byte(certificate)); err != nil {
+			errs = append(errs, ValidationError{"certificate", ValidationInvalidPEM, err.Error()})
+		} else if len(certs) > 1 {
+			errs = append(errs, ValidationError{"certificate", ValidationMultiplePEMBlocks, fmt.Sprintf("found %d certificates, intermediate certificates should be put into intermediate_certificate", len(certs))})
+		} else {
+			leaf = certs[0]
+		}
+	}
+
+	if leaf != nil {
+		if now.After(leaf.NotAfter) {
+			errs = append(errs, ValidationError{"certificate", ValidationExpired, fmt.Sprintf("certificate expired at %s", leaf.NotAfter.Format(time.RFC3339))})
+		}
+		if now.Before(leaf.NotBefore) {
+			errs = append(errs, ValidationError{"certificate", ValidationNotYetValid, fmt.Sprintf("certificate is not valid until %s", leaf.NotBefore.Format(time.RFC3339))})
+		}
+		if err := CheckKeyType(leaf.PublicKey); err != nil {
+			errs = append(errs, ValidationError{"certificate", ValidationUnsupportedKeyType, err.Error()})
+		}
+	}
+
+	// 私钥（可能前面带有 openssl 生成的 EC PARAMETERS ）
+	var key crypto.Signer
+	if privateKey != "" {
+		if blocks, err := DecodePEMBlocks([]byte(privateKey)); err != nil {
+			errs = append(errs, ValidationError{"private_key", ValidationInvalidPEM, err.Error()})
+		} else {
+			var keyBlocks []*pem.Block
+			for _, block := range blocks {
+				if block.Type != "EC PARAMETERS" {
+					keyBlocks = append(keyBlocks, block)
+				}
+			}
+			if len(keyBlocks) != 1 {
+				errs = append(errs, ValidationError{"private_key", ValidationMultiplePEMBlocks, fmt.Sprintf("expected exactly 1 private key, found %d pem blocks", len(keyBlocks))})
+			} else if key, err = ParsePrivateKeyDER(keyBlocks[0].Bytes); err != nil {
+				errs = append(errs, ValidationError{"private_key", ValidationInvalidPEM, err.Error()})
+			} else if err = CheckKeyType(key.Public()); err != nil {
+				errs = append(errs, ValidationError{"private_key", ValidationUnsupportedKeyType, err.Error()})
+				key = nil
+			}
+		}
+	} else if certificate != "" {
+		errs = append(errs, ValidationError{"private_key", ValidationMissingPrivateKey, "certificate requires a private key"})
+	}
+
+	// 私钥与证书是否匹配
+	if leaf != nil && key != nil {
+		if pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !pub.Equal(leaf.PublicKey) {
+			errs = append(errs, ValidationError{"private_key", ValidationKeyMismatch, "private key does not match the certificate"})
+		}
+	}
+
+	// 中间证书链需要逐级签发（可以不包含根证书）
+	if intermediate != "" {
+		if chain, err := ParseCertificatesPEM([]byte(intermediate)); err != nil {
+			errs = append(errs, ValidationError{"intermediate_certificate", ValidationInvalidPEM, err.Error()})
+		} else if leaf != nil {
+			if err := CheckChain(leaf, chain); err != nil {
+				errs = append(errs, ValidationError{"intermediate_certificate", ValidationChainBroken, err.Error()})
+			}
+			for _, ca := range chain {
+				if now.After(ca.NotAfter) {
+					errs = append(errs, ValidationError{"intermediate_certificate", ValidationExpired, fmt.Sprintf("intermediate certificate %q expired at %s", ca.Subject.CommonName, ca.NotAfter.Format(time.RFC3339))})
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// CheckChain 检查从叶子证书开始，每一张证书都由下一张签发
+func CheckChain(leaf *x509.Certificate, chain []*x509.Certificate) error {
+	child := leaf
+	for _, parent := range chain {
+		if err := child.CheckSignatureFrom(parent); err != nil {
+			return fmt.Errorf("%q is not issued by %q: %w", child.Subject.CommonName, parent.Subject.CommonName, err)
+		}
+		child = parent
+	}
+
+	return nil
+}
@@ -1,14 +1,24 @@
 package config
 
+import "time"
+
 type Config struct {
 	System struct {
 		IsProd                bool   // 是否为生产环境
 		Listen                string // 监听地址
 		DBConnectionString    string // Postgres 数据库的连接字符串
 		RedisConnectionString string // Redis 数据库的连接字符串
+		PublicEndpoint        string // 实例访问 server 使用的地址，用于转发 ACME HTTP-01 验证请求，留空则不转发
 	}
 	Security struct {
-		EncryptSecretKey   string // 加密密钥，用于加密数据库中的敏感信息（例如证书），设定后不能更改
-		SignatureSecretKey string // 签名密钥，用于产生签名（例如 JWT ），更新会导致旧有会话失效，但不影响使用
+		EncryptSecretKeys   map[string]string // 加密密钥（ ID => 密钥），用于加密数据库中的敏感信息（例如证书），可以同时存在多个以便轮换
+		EncryptPrimaryKeyID string            // 加密新数据使用的密钥 ID ，旧数据可以通过重新加密任务迁移过来
+		EncryptLegacyKeyID  string            // 解密没有版本信息的旧数据使用的密钥 ID ，即 ENCRYPT_SECRET_KEY
+		SignatureSecretKey  string            // 签名密钥，用于产生签名（例如 JWT ），更新会导致旧有会话失效，但不影响使用
+	}
+	CertRenew struct {
+		Interval time.Duration // 检查需要续期的证书的间隔
+		Window   time.Duration // 在过期前多久开始自动续期
+		Backoff  time.Duration // 续期失败后重试的基础间隔，连续失败时翻倍
 	}
 }
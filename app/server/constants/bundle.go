@@ -0,0 +1,50 @@
+package constants
+
+// 同步包（ tar.gz ）中的文件名
+const (
+	BundleManifestName = "manifest.json"
+	BundleConfigName   = "Caddyfile"
+	BundleFileEntry    = "files/%d" // %d -> 文件在清单中的序号
+)
+
+// 手动部署的导出包中的文件名
+const (
+	ExportFileName      = "cdn-instance-%d.tar.gz" // %d -> instance id
+	ExportConfigName    = "Caddyfile"
+	ExportChecksumName  = "SHA256SUMS"
+	ExportInstallName   = "install.sh"
+	ExportInstallScript = `#!/bin/sh
+# 安装导出的配置与文件，在解压后的目录中运行
+#   DESTDIR   文件安装的根目录，默认为 /
+#   CADDYFILE Caddyfile 的安装位置，默认为 /etc/caddy/Caddyfile
+set -eu
+
+cd "$(dirname "$0")"
+
+DESTDIR="${DESTDIR:-}"
+CADDYFILE="${CADDYFILE:-/etc/caddy/Caddyfile}"
+
+# 校验文件完整性
+sha256sum -c --quiet SHA256SUMS
+
+# 安装证书与额外文件
+if [ -d data ]; then
+	mkdir -p "$DESTDIR/data"
+	cp -Rp data/. "$DESTDIR/data/"
+fi
+
+# 安装配置
+mkdir -p "$(dirname "$CADDYFILE")"
+cp Caddyfile "$CADDYFILE"
+
+# 重新加载 Caddy
+if command -v caddy >/dev/null 2>&1; then
+	caddy reload --config "$CADDYFILE" --adapter caddyfile || echo "caddy reload failed, please reload caddy manually" >&2
+else
+	echo "caddy not found, please reload caddy manually" >&2
+fi
+`
+)
+
+// ExportRecordFormat 导出包中包含的证书在导出记录中的格式
+const ExportRecordFormat = "instance"
@@ -3,11 +3,15 @@ package constants
 import "time"
 
 const (
-	CacheKeyInstanceInfo      = "cdn:instance:info:%d"      // 主要是认证使用
-	CacheKeyInstanceConfig    = "cdn:instance:config:%d"    // 存储配置文件（ Caddyfile ）
-	CacheKeyInstanceFiles     = "cdn:instance:files:%d"     // 针对不同实例设置不同的缓存表，是因为可能会有不同内容的同名文件
-	CacheKeyInstanceHeartbeat = "cdn:instance:heartbeat:%d" // 存储心跳数据，即各个文件的更新时间戳
-	CacheKeyInstanceLastseen  = "cdn:instance:lastseen:%d"  // 存储上一次心跳通信时间，用于判断是否在线
+	CacheKeyInstanceInfo      = "cdn:instance:info:%d"         // 主要是认证使用
+	CacheKeyInstanceConfig    = "cdn:instance:config:v2:%d"    // 存储配置文件（ Caddyfile ）；文件路径改为相对数据根目录后加上了 v2 ，避免读到旧格式的缓存
+	CacheKeyInstanceFiles     = "cdn:instance:files:v2:%d"     // 针对不同实例设置不同的缓存表，是因为可能会有不同内容的同名文件
+	CacheKeyInstanceHeartbeat = "cdn:instance:heartbeat:v2:%d" // 存储心跳数据，即各个文件的更新时间戳
+	CacheKeyInstanceLastseen  = "cdn:instance:lastseen:%d"     // 存储上一次心跳通信时间，用于判断是否在线
+	CacheKeyInstanceReport    = "cdn:instance:report:%d"       // 存储实例上报的最近一次同步结果
+	CacheKeyInstanceDrift     = "cdn:instance:drift:%d"        // 存储实例上报的最近一次配置漂移，不会被之后的普通上报覆盖
+	CacheKeyAcmeHTTP01        = "cdn:acme:http01:%s"           // ACME HTTP-01 验证内容，所有副本共享，由实例转发来的请求读取
+	CacheKeyReencryptStatus   = "cdn:reencrypt:status"         // 重新加密任务的进度，不过期
 )
 
 const (
@@ -15,4 +19,12 @@ const (
 	CacheExpireInstanceConfig    = 12 * time.Hour
 	CacheExpireInstanceHeartbeat = 1 * time.Hour
 	CacheExpireInstanceLastseen  = 12 * time.Hour
+	CacheExpireInstanceReport    = 7 * 24 * time.Hour
+	CacheExpireAcmeHTTP01        = CertIssueTimeout
+)
+
+// 分布式锁，在多个 server 副本之间协调
+const (
+	LockKeyCertRenew = "cdn:lock:cert:renew:%d" // 证书续期，保证同一时间只有一个副本在续期同一张证书
+	LockKeyReencrypt = "cdn:lock:reencrypt"     // 重新加密，全局只运行一个任务
 )
@@ -0,0 +1,28 @@
+package constants
+
+import "time"
+
+const (
+	CertIssueTimeout    = 5 * time.Minute                  // 单次签发（包括验证）的最长时间
+	CertRenewLockTTL    = CertIssueTimeout + 1*time.Minute // 续期锁的有效期，需要比签发时间更长
+	CertRenewBackoffMax = 24 * time.Hour                   // 连续失败时重试间隔的上限
+)
+
+const (
+	CertInternalCAValidity = 7 * 24 * time.Hour        // 内置 CA 签发证书的默认有效期
+	CAValidityRoot         = 10 * 365 * 24 * time.Hour // 新建根证书的默认有效期
+	CAValidityIntermediate = 5 * 365 * 24 * time.Hour  // 新建中间证书的默认有效期
+)
+
+const (
+	CertRenewTriggerManual    = "manual"
+	CertRenewTriggerScheduled = "scheduled"
+)
+
+const (
+	CertVersionSourceUpload   = "upload"
+	CertVersionSourceImport   = "import"
+	CertVersionSourceRenew    = "renew"
+	CertVersionSourceRollback = "rollback"
+	CertVersionSourceSnapshot = "snapshot" // 覆盖之前补记的原有内容
+)
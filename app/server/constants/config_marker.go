@@ -0,0 +1,7 @@
+package constants
+
+// 渲染配置时在每一段前面加上的注释，实例用它把 Caddy 报告的行号对应到站点
+const (
+	ConfigMarkerPreConfig = "# cdn:preconfig"
+	ConfigMarkerSite      = "# cdn:site %d" // %d -> site id
+)
@@ -1,15 +1,27 @@
 package constants
 
+// 实例上的数据根目录，下发给实例的文件路径都相对于它
+const (
+	DataRootPlaceholder = "{$CDN_DATA_ROOT}" // 渲染配置时使用，由实例替换为自己的数据根目录（未替换时 Caddy 会从同名环境变量读取）
+	DataRootDefault     = "/data/cdn"        // 导出手动部署的实例时使用
+)
+
 // 证书文件
 const (
-	CertPathPrefix           = "/data/cdn/certs/"
+	CertPathPrefix           = "certs/"
 	CertPathDir              = CertPathPrefix + "%d/" // %d -> cert id
 	CertPathCertName         = "cert.pem"
 	CertPathKeyName          = "key.pem"
 	CertPathIntermediateName = "ca.pem"
 )
 
+// CA 证书（作为信任根下发）
+const (
+	CAPathPrefix = "cas/"
+	CAPathFile   = CAPathPrefix + "%d.pem" // %d -> ca id
+)
+
 // 额外文件
 const (
-	AFilePathPrefix = "/data/cdn/afiles/" // Additional File
+	AFilePathPrefix = "afiles/" // Additional File
 )
@@ -0,0 +1,10 @@
+package constants
+
+import "time"
+
+const (
+	PubSubChannelInstanceNotify = "cdn:instance:notify" // 实例数据变化通知，消息内容为实例 ID ，所有副本都订阅，再转发给各自连接着的实例
+
+	InstanceEventsKeepalive = 25 * time.Second // 事件流的保活间隔，避免被代理当作空闲连接断开
+	InstanceEventsRetry     = 5 * time.Second  // 告知实例断线后的重连间隔
+)
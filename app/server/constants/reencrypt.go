@@ -0,0 +1,16 @@
+package constants
+
+import "time"
+
+const (
+	ReencryptBatchSize       = 100              // 每批读取的记录数
+	ReencryptLockTTL         = 1 * time.Minute  // 任务锁的有效期，运行期间定期续期；进程退出后很快过期，允许重新开始（单条记录的迁移是幂等的）
+	ReencryptLockRefreshTime = 20 * time.Second // 任务锁的续期间隔
+)
+
+const (
+	ReencryptStateIdle    = "idle"
+	ReencryptStateRunning = "running"
+	ReencryptStateDone    = "done"
+	ReencryptStateFailed  = "failed"
+)
@@ -0,0 +1,128 @@
+package dnsprovider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultPropagationTimeout = 2 * time.Minute
+	defaultPollingInterval    = 2 * time.Second
+)
+
+// Provider 具体的 DNS 服务提供方，负责创建和清理 TXT 记录
+type Provider interface {
+	Present(ctx context.Context, fqdn string, value string) error
+	CleanUp(ctx context.Context, fqdn string, value string) error
+}
+
+// Authoritative 可选接口：提供方知道自己的权威服务器时，直接用于检查记录是否生效
+type Authoritative interface {
+	Nameservers() []string
+}
+
+// Config 所有提供方通用的配置， type 用于选择提供方，其他字段由各提供方自行解析
+type Config struct {
+	Type               string   `json:"type"`
+	PropagationTimeout string   `json:"propagation_timeout,omitempty"` // 等待记录生效的最长时间，默认 2m
+	PollingInterval    string   `json:"polling_interval,omitempty"`    // 检查记录是否生效的间隔，默认 2s
+	Nameservers        []string `json:"nameservers,omitempty"`         // 用于检查记录是否生效的服务器，留空则使用提供方的权威服务器或自动查找
+}
+
+type DNS struct {
+	Provider
+
+	propagationTimeout time.Duration
+	pollingInterval    time.Duration
+	nameservers        []string
+}
+
+func New(raw json.RawMessage) (*DNS, error) {
+	var cfg Config
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal dns config: %w", err)
+	}
+
+	d := &DNS{
+		propagationTimeout: defaultPropagationTimeout,
+		pollingInterval:    defaultPollingInterval,
+	}
+
+	if cfg.PropagationTimeout != "" {
+		timeout, err := time.ParseDuration(cfg.PropagationTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid propagation timeout: %w", err)
+		} else if timeout <= 0 {
+			return nil, errors.New("propagation timeout should be positive")
+		}
+		d.propagationTimeout = timeout
+	}
+	if cfg.PollingInterval != "" {
+		interval, err := time.ParseDuration(cfg.PollingInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid polling interval: %w", err)
+		} else if interval <= 0 {
+			return nil, errors.New("polling interval should be positive")
+		}
+		d.pollingInterval = interval
+	}
+
+	// 选择提供方
+	var err error
+	switch cfg.Type {
+	case "rfc2136":
+		d.Provider, err = newRFC2136(raw)
+	case "":
+		return nil, errors.New("dns provider type is empty")
+	default:
+		return nil, fmt.Errorf("unsupported dns provider type %q", cfg.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s provider: %w", cfg.Type, err)
+	}
+
+	// 确定检查用的服务器
+	if len(cfg.Nameservers) > 0 {
+		d.nameservers = withDefaultPort(cfg.Nameservers)
+	} else if auth, ok := d.Provider.(Authoritative); ok {
+		d.nameservers = auth.Nameservers()
+	}
+
+	return d, nil
+}
+
+// WaitPropagation 等待所有权威服务器都能查询到指定的 TXT 记录
+func (d *DNS) WaitPropagation(ctx context.Context, fqdn string, value string) error {
+	ctx, cancel := context.WithTimeout(ctx, d.propagationTimeout)
+	defer cancel()
+
+	nameservers := d.nameservers
+	if len(nameservers) == 0 {
+		var err error
+		if nameservers, err = findAuthoritativeNameservers(ctx, fqdn); err != nil {
+			return fmt.Errorf("failed to find authoritative nameservers: %w", err)
+		}
+	}
+
+	ticker := time.NewTicker(d.pollingInterval)
+	defer ticker.Stop()
+
+	for {
+		ready, err := checkTXT(ctx, fqdn, value, nameservers)
+		if ready {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("txt record %s not propagated: %w", fqdn, err)
+			}
+			return fmt.Errorf("txt record %s not propagated: %w", fqdn, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package dnsprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/miekg/dns"
+	"net"
+	"strings"
+)
+
+const resolvConfPath = "/etc/resolv.conf"
+
+func withDefaultPort(servers []string) []string {
+	var res []string
+	for _, server := range servers {
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		res = append(res, server)
+	}
+	return res
+}
+
+func exchange(ctx context.Context, m *dns.Msg, server string) (*dns.Msg, error) {
+	c := new(dns.Client)
+	r, _, err := c.ExchangeContext(ctx, m, server)
+	if err != nil {
+		return nil, err
+	}
+
+	// 响应被截断时改用 TCP 重试
+	if r.Truncated {
+		c.Net = "tcp"
+		if r, _, err = c.ExchangeContext(ctx, m, server); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// findZone 从目标域名开始逐级向上查询 SOA 记录，找到其所在的区域
+func findZone(ctx context.Context, fqdn string, server string) (string, error) {
+	for _, index := range dns.Split(fqdn) {
+		name := fqdn[index:]
+
+		m := new(dns.Msg)
+		m.SetQuestion(name, dns.TypeSOA)
+		r, err := exchange(ctx, m, server)
+		if err != nil {
+			return "", fmt.Errorf("query soa of %s: %w", name, err)
+		}
+		if r.Rcode != dns.RcodeSuccess && r.Rcode != dns.RcodeNameError {
+			return "", fmt.Errorf("query soa of %s: %s", name, dns.RcodeToString[r.Rcode])
+		}
+
+		for _, rr := range r.Answer {
+			if soa, ok := rr.(*dns.SOA); ok && strings.EqualFold(soa.Hdr.Name, name) {
+				return soa.Hdr.Name, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("no zone found for %s", fqdn)
+}
+
+// findAuthoritativeNameservers 通过系统解析器找到域名所在区域的所有权威服务器
+func findAuthoritativeNameservers(ctx context.Context, fqdn string) ([]string, error) {
+	conf, err := dns.ClientConfigFromFile(resolvConfPath)
+	if err != nil {
+		return nil, fmt.Errorf("load resolv.conf: %w", err)
+	}
+	if len(conf.Servers) == 0 {
+		return nil, errors.New("no system resolver configured")
+	}
+	resolver := net.JoinHostPort(conf.Servers[0], conf.Port)
+
+	zone, err := findZone(ctx, fqdn, resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	m := new(dns.Msg)
+	m.SetQuestion(zone, dns.TypeNS)
+	r, err := exchange(ctx, m, resolver)
+	if err != nil {
+		return nil, fmt.Errorf("query ns of %s: %w", zone, err)
+	}
+
+	var nameservers []string
+	for _, rr := range r.Answer {
+		if ns, ok := rr.(*dns.NS); ok {
+			nameservers = append(nameservers, net.JoinHostPort(strings.TrimSuffix(ns.Ns, "."), "53"))
+		}
+	}
+	if len(nameservers) == 0 {
+		return nil, fmt.Errorf("no ns records found for %s", zone)
+	}
+
+	return nameservers, nil
+}
+
+// checkTXT 检查所有服务器上是否都已经有了目标 TXT 记录
+func checkTXT(ctx context.Context, fqdn string, value string, nameservers []string) (bool, error) {
+	for _, server := range nameservers {
+		m := new(dns.Msg)
+		m.SetQuestion(fqdn, dns.TypeTXT)
+		m.RecursionDesired = false
+
+		r, err := exchange(ctx, m, server)
+		if err != nil {
+			return false, fmt.Errorf("query txt from %s: %w", server, err)
+		}
+
+		found := false
+		for _, rr := range r.Answer {
+			if txt, ok := rr.(*dns.TXT); ok && strings.Join(txt.Txt, "") == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false, fmt.Errorf("txt record not found on %s", server)
+		}
+	}
+
+	return true, nil
+}
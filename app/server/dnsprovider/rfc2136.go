@@ -0,0 +1,136 @@
+package dnsprovider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/miekg/dns"
+	"time"
+)
+
+// RFC2136 通过 DNS 动态更新（可选 TSIG 签名）管理记录，适用于 BIND 、 Knot 、 PowerDNS 等自建权威服务器
+type RFC2136 struct {
+	nameserver    string
+	zone          string
+	tsigKey       string
+	tsigSecret    string
+	tsigAlgorithm string
+	ttl           uint32
+	timeout       time.Duration
+}
+
+type rfc2136Config struct {
+	Nameserver    string `json:"nameserver"`               // 接受更新的主服务器，例如 127.0.0.1:53
+	Zone          string `json:"zone,omitempty"`           // 所在区域，留空则自动查找
+	TSIGKey       string `json:"tsig_key,omitempty"`       // TSIG 密钥名称
+	TSIGSecret    string `json:"tsig_secret,omitempty"`    // TSIG 密钥（ base64 ）
+	TSIGAlgorithm string `json:"tsig_algorithm,omitempty"` // TSIG 算法，默认 hmac-sha256.
+	TTL           uint32 `json:"ttl,omitempty"`            // 记录 TTL ，默认 60
+	Timeout       string `json:"timeout,omitempty"`        // 单次请求超时，默认 10s
+}
+
+var _ Provider = (*RFC2136)(nil)
+var _ Authoritative = (*RFC2136)(nil)
+
+func newRFC2136(raw json.RawMessage) (*RFC2136, error) {
+	var cfg rfc2136Config
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	if cfg.Nameserver == "" {
+		return nil, errors.New("nameserver is empty")
+	}
+	if (cfg.TSIGKey == "") != (cfg.TSIGSecret == "") {
+		return nil, errors.New("tsig key and secret should be set together")
+	}
+
+	p := &RFC2136{
+		nameserver:    withDefaultPort([]string{cfg.Nameserver})[0],
+		tsigSecret:    cfg.TSIGSecret,
+		tsigAlgorithm: dns.HmacSHA256,
+		ttl:           60,
+		timeout:       10 * time.Second,
+	}
+	if cfg.Zone != "" {
+		p.zone = dns.Fqdn(cfg.Zone)
+	}
+	if cfg.TSIGKey != "" {
+		p.tsigKey = dns.Fqdn(cfg.TSIGKey)
+	}
+	if cfg.TSIGAlgorithm != "" {
+		p.tsigAlgorithm = dns.Fqdn(cfg.TSIGAlgorithm)
+	}
+	if cfg.TTL > 0 {
+		p.ttl = cfg.TTL
+	}
+	if cfg.Timeout != "" {
+		timeout, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout: %w", err)
+		} else if timeout <= 0 {
+			return nil, errors.New("timeout should be positive")
+		}
+		p.timeout = timeout
+	}
+
+	return p, nil
+}
+
+func (p *RFC2136) Nameservers() []string {
+	return []string{p.nameserver}
+}
+
+func (p *RFC2136) Present(ctx context.Context, fqdn string, value string) error {
+	return p.update(ctx, fqdn, value, true)
+}
+
+func (p *RFC2136) CleanUp(ctx context.Context, fqdn string, value string) error {
+	return p.update(ctx, fqdn, value, false)
+}
+
+func (p *RFC2136) update(ctx context.Context, fqdn string, value string, insert bool) error {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	fqdn = dns.Fqdn(fqdn)
+
+	zone := p.zone
+	if zone == "" {
+		var err error
+		if zone, err = findZone(ctx, fqdn, p.nameserver); err != nil {
+			return err
+		}
+	}
+
+	rr := &dns.TXT{
+		Hdr: dns.RR_Header{Name: fqdn, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: p.ttl},
+		Txt: []string{value},
+	}
+
+	// 只增删这一条记录，同名的其他记录（例如通配符和主域名同时验证时）不受影响
+	m := new(dns.Msg)
+	m.SetUpdate(zone)
+	if insert {
+		m.Insert([]dns.RR{rr})
+	} else {
+		m.Remove([]dns.RR{rr})
+	}
+
+	c := new(dns.Client)
+	if p.tsigKey != "" {
+		m.SetTsig(p.tsigKey, p.tsigAlgorithm, 300, time.Now().Unix())
+		c.TsigSecret = map[string]string{p.tsigKey: p.tsigSecret}
+	}
+
+	r, _, err := c.ExchangeContext(ctx, m, p.nameserver)
+	if err != nil {
+		return fmt.Errorf("send dns update: %w", err)
+	}
+	if r.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("dns update rejected: %s", dns.RcodeToString[r.Rcode])
+	}
+
+	return nil
+}
@@ -0,0 +1,283 @@
+package dnsprovider
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/miekg/dns"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testZone       = "example.com."
+	testTSIGKey    = "update-key."
+	testTSIGSecret = "c2VjcmV0LXNlY3JldC1zZWNyZXQtc2VjcmV0"
+)
+
+// fakeAuthority 进程内的权威服务器，只接受带有正确 TSIG 签名的动态更新
+type fakeAuthority struct {
+	addr string
+
+	mu      sync.Mutex
+	records map[string][]string // 域名 -> TXT 记录
+	updates int                 // 接受的更新次数
+}
+
+func newFakeAuthority(t *testing.T) *fakeAuthority {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fa := &fakeAuthority{
+		addr:    pc.LocalAddr().String(),
+		records: make(map[string][]string),
+	}
+
+	started := make(chan struct{})
+	server := &dns.Server{
+		PacketConn:        pc,
+		Handler:           dns.HandlerFunc(fa.serve),
+		TsigSecret:        map[string]string{testTSIGKey: testTSIGSecret},
+		NotifyStartedFunc: func() { close(started) },
+		// 默认只接受查询与通知，需要放行动态更新
+		MsgAcceptFunc: func(dh dns.Header) dns.MsgAcceptAction {
+			if int(dh.Bits>>11)&0xF == dns.OpcodeUpdate {
+				return dns.MsgAccept
+			}
+			return dns.DefaultMsgAcceptFunc(dh)
+		},
+	}
+	go func() {
+		_ = server.ActivateAndServe()
+	}()
+	<-started
+	t.Cleanup(func() {
+		_ = server.Shutdown()
+	})
+
+	return fa
+}
+
+func (fa *fakeAuthority) serve(w dns.ResponseWriter, r *dns.Msg) {
+	fa.mu.Lock()
+	defer fa.mu.Unlock()
+
+	m := new(dns.Msg)
+	m.SetReply(r)
+	m.Authoritative = true
+
+	if r.Opcode == dns.OpcodeUpdate {
+		// 更新必须签名，并且签名正确
+		if r.IsTsig() == nil || w.TsigStatus() != nil {
+			m.Rcode = dns.RcodeNotAuth
+			_ = w.WriteMsg(m)
+			return
+		}
+		if len(r.Question) != 1 || r.Question[0].Name != testZone {
+			m.Rcode = dns.RcodeNotZone
+		} else {
+			for _, rr := range r.Ns {
+				txt, ok := rr.(*dns.TXT)
+				if !ok {
+					continue
+				}
+				value := strings.Join(txt.Txt, "")
+				switch txt.Hdr.Class {
+				case dns.ClassINET: // 添加
+					fa.records[txt.Hdr.Name] = append(fa.records[txt.Hdr.Name], value)
+				case dns.ClassNONE: // 删除指定的记录
+					var kept []string
+					for _, v := range fa.records[txt.Hdr.Name] {
+						if v != value {
+							kept = append(kept, v)
+						}
+					}
+					fa.records[txt.Hdr.Name] = kept
+				}
+			}
+			fa.updates++
+		}
+		m.SetTsig(testTSIGKey, dns.HmacSHA256, 300, time.Now().Unix())
+		_ = w.WriteMsg(m)
+		return
+	}
+
+	// 普通查询
+	q := r.Question[0]
+	switch {
+	case q.Qtype == dns.TypeSOA && q.Name == testZone:
+		m.Answer = append(m.Answer, &dns.SOA{
+			Hdr:     dns.RR_Header{Name: testZone, Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 60},
+			Ns:      "ns1." + testZone,
+			Mbox:    "hostmaster." + testZone,
+			Serial:  1,
+			Refresh: 3600,
+			Retry:   600,
+			Expire:  86400,
+			Minttl:  60,
+		})
+	case q.Qtype == dns.TypeTXT:
+		for _, value := range fa.records[q.Name] {
+			m.Answer = append(m.Answer, &dns.TXT{
+				Hdr: dns.RR_Header{Name: q.Name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: 60},
+				Txt: []string{value},
+			})
+		}
+	}
+	if len(m.Answer) == 0 && !dns.IsSubDomain(testZone, q.Name) {
+		m.Rcode = dns.RcodeNameError
+	}
+	_ = w.WriteMsg(m)
+}
+
+func (fa *fakeAuthority) txt(name string) []string {
+	fa.mu.Lock()
+	defer fa.mu.Unlock()
+	return append([]string(nil), fa.records[name]...)
+}
+
+func newTestDNS(t *testing.T, cfg map[string]any) *DNS {
+	cfg["type"] = "rfc2136"
+	cfg["polling_interval"] = "10ms"
+	if _, ok := cfg["propagation_timeout"]; !ok {
+		cfg["propagation_timeout"] = "2s"
+	}
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := New(raw)
+	if err != nil {
+		t.Fatalf("new dns provider: %v", err)
+	}
+	return d
+}
+
+func TestRFC2136PresentAndCleanUp(t *testing.T) {
+	tests := []struct {
+		name string
+		zone string
+	}{
+		{"configured zone", "example.com"},
+		{"find zone by soa", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fa := newFakeAuthority(t)
+			d := newTestDNS(t, map[string]any{
+				"nameserver":  fa.addr,
+				"zone":        tt.zone,
+				"tsig_key":    strings.TrimSuffix(testTSIGKey, "."),
+				"tsig_secret": testTSIGSecret,
+			})
+
+			ctx := context.Background()
+			fqdn := "_acme-challenge.www.example.com."
+
+			// 通配符与主域名同时验证时同名下会有两条记录
+			for _, value := range []string{"value-1", "value-2"} {
+				if err := d.Present(ctx, fqdn, value); err != nil {
+					t.Fatalf("present %s: %v", value, err)
+				}
+				if err := d.WaitPropagation(ctx, fqdn, value); err != nil {
+					t.Fatalf("wait propagation %s: %v", value, err)
+				}
+			}
+			if got := fa.txt(fqdn); len(got) != 2 {
+				t.Fatalf("expected 2 records, got %v", got)
+			}
+
+			// 清理只删除对应的那一条
+			if err := d.CleanUp(ctx, fqdn, "value-1"); err != nil {
+				t.Fatalf("clean up: %v", err)
+			}
+			if got := fa.txt(fqdn); len(got) != 1 || got[0] != "value-2" {
+				t.Fatalf("expected only value-2 left, got %v", got)
+			}
+		})
+	}
+}
+
+func TestRFC2136RejectsBadTSIG(t *testing.T) {
+	fa := newFakeAuthority(t)
+
+	tests := []struct {
+		name string
+		cfg  map[string]any
+	}{
+		{"wrong secret", map[string]any{
+			"nameserver":  fa.addr,
+			"zone":        "example.com",
+			"tsig_key":    "update-key",
+			"tsig_secret": "d3Jvbmctc2VjcmV0",
+		}},
+		{"unsigned", map[string]any{
+			"nameserver": fa.addr,
+			"zone":       "example.com",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDNS(t, tt.cfg)
+			err := d.Present(context.Background(), "_acme-challenge.example.com.", "value")
+			if err == nil {
+				t.Fatal("expected update to be rejected")
+			}
+		})
+	}
+
+	fa.mu.Lock()
+	defer fa.mu.Unlock()
+	if fa.updates != 0 {
+		t.Fatalf("expected no accepted updates, got %d", fa.updates)
+	}
+}
+
+func TestRFC2136WaitPropagationTimeout(t *testing.T) {
+	fa := newFakeAuthority(t)
+	d := newTestDNS(t, map[string]any{
+		"nameserver":          fa.addr,
+		"zone":                "example.com",
+		"propagation_timeout": "100ms",
+	})
+
+	err := d.WaitPropagation(context.Background(), "_acme-challenge.example.com.", "missing")
+	if err == nil || !strings.Contains(err.Error(), "not propagated") {
+		t.Fatalf("expected propagation timeout, got %v", err)
+	}
+}
+
+func TestNewRFC2136Config(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     string
+		wantErr bool
+	}{
+		{"minimal", `{"type":"rfc2136","nameserver":"127.0.0.1"}`, false},
+		{"missing nameserver", `{"type":"rfc2136"}`, true},
+		{"tsig key without secret", `{"type":"rfc2136","nameserver":"127.0.0.1","tsig_key":"k"}`, true},
+		{"invalid timeout", `{"type":"rfc2136","nameserver":"127.0.0.1","timeout":"soon"}`, true},
+		{"zero timeout", `{"type":"rfc2136","nameserver":"127.0.0.1","timeout":"0s"}`, true},
+		{"negative propagation timeout", `{"type":"rfc2136","nameserver":"127.0.0.1","propagation_timeout":"-1m"}`, true},
+		{"zero polling interval", `{"type":"rfc2136","nameserver":"127.0.0.1","polling_interval":"0s"}`, true},
+		{"unknown type", `{"type":"route53"}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := New(json.RawMessage(tt.cfg))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if err == nil && d.nameservers[0] != "127.0.0.1:53" {
+				t.Fatalf("expected default port, got %v", d.nameservers)
+			}
+		})
+	}
+}
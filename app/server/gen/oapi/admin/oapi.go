@@ -23,6 +23,13 @@ const (
 	JWTAuthScopes = "JWTAuth.Scopes"
 )
 
+// Defines values for CertExportRequestFormat.
+const (
+	Pem    CertExportRequestFormat = "pem"
+	Pkcs12 CertExportRequestFormat = "pkcs12"
+	Zip    CertExportRequestFormat = "zip"
+)
+
 // AdditionalFileInfoFile defines model for AdditionalFileInfoFile.
 type AdditionalFileInfoFile struct {
 	Content *openapi_types.File `json:"content,omitempty"`
@@ -48,6 +55,104 @@ type AdditionalFileListResponse struct {
 	PageMax *PageMax                    `json:"page_max,omitempty"`
 }
 
+// CACreateInput defines model for CACreateInput.
+type CACreateInput struct {
+	CommonName string `json:"common_name"`
+
+	// KeyType ec256 (default) / ec384 / rsa2048 / rsa4096
+	KeyType  *string   `json:"key_type,omitempty"`
+	Name     *string   `json:"name,omitempty"`
+	ParentId *ObjectID `json:"parent_id,omitempty"`
+
+	// Validity Go duration, defaults to 87600h for root and 43800h for intermediate
+	Validity *string `json:"validity,omitempty"`
+}
+
+// CAImportInput defines model for CAImportInput.
+type CAImportInput struct {
+	Certificate string    `json:"certificate"`
+	Name        *string   `json:"name,omitempty"`
+	ParentId    *ObjectID `json:"parent_id,omitempty"`
+
+	// PrivateKey optional, a ca without private key can only be distributed as a trust root
+	PrivateKey *string `json:"private_key,omitempty"`
+}
+
+// CAInfoInput defines model for CAInfoInput.
+type CAInfoInput struct {
+	Name *string `json:"name,omitempty"`
+}
+
+// CAInfoWithID defines model for CAInfoWithID.
+type CAInfoWithID struct {
+	Certificate *string `json:"certificate,omitempty"`
+
+	// ExpiresAt unix second
+	ExpiresAt     *Timestamp `json:"expires_at,omitempty"`
+	HasPrivateKey *bool      `json:"has_private_key,omitempty"`
+	Id            *ObjectID  `json:"id,omitempty"`
+	Name          *string    `json:"name,omitempty"`
+	ParentId      *ObjectID  `json:"parent_id,omitempty"`
+}
+
+// CAListResponse defines model for CAListResponse.
+type CAListResponse struct {
+	Limit   *int            `json:"limit,omitempty"`
+	List    *[]CAInfoWithID `json:"list,omitempty"`
+	PageMax *PageMax        `json:"page_max,omitempty"`
+}
+
+// CertCSRInput defines model for CertCSRInput.
+type CertCSRInput struct {
+	Country *string  `json:"country,omitempty"`
+	Domains []string `json:"domains"`
+
+	// KeyType ec256 (default) / ec384 / rsa2048 / rsa4096
+	KeyType            *string `json:"key_type,omitempty"`
+	Locality           *string `json:"locality,omitempty"`
+	Name               *string `json:"name,omitempty"`
+	Organization       *string `json:"organization,omitempty"`
+	OrganizationalUnit *string `json:"organizational_unit,omitempty"`
+	Province           *string `json:"province,omitempty"`
+}
+
+// CertExportHistoryResponse defines model for CertExportHistoryResponse.
+type CertExportHistoryResponse struct {
+	Limit   *int                `json:"limit,omitempty"`
+	List    *[]CertExportRecord `json:"list,omitempty"`
+	PageMax *PageMax            `json:"page_max,omitempty"`
+}
+
+// CertExportRecord defines model for CertExportRecord.
+type CertExportRecord struct {
+	// ExportedAt unix second
+	ExportedAt *Timestamp `json:"exported_at,omitempty"`
+	Format     *string    `json:"format,omitempty"`
+	Id         *ObjectID  `json:"id,omitempty"`
+	Ip         *string    `json:"ip,omitempty"`
+	UserId     *ObjectID  `json:"user_id,omitempty"`
+}
+
+// CertExportRequest defines model for CertExportRequest.
+type CertExportRequest struct {
+	Format CertExportRequestFormat `json:"format"`
+
+	// Password required for pkcs12
+	Password *string `json:"password,omitempty"`
+}
+
+// CertExportRequestFormat defines model for CertExportRequest.Format.
+type CertExportRequestFormat string
+
+// CertImportInput defines model for CertImportInput.
+type CertImportInput struct {
+	Content openapi_types.File `json:"content"`
+	Name    *string            `json:"name,omitempty"`
+
+	// Password password of the pkcs12 bundle
+	Password *string `json:"password,omitempty"`
+}
+
 // CertInfoInput defines model for CertInfoInput.
 type CertInfoInput struct {
 	Certificate             *string   `json:"certificate,omitempty"`
@@ -57,14 +162,24 @@ type CertInfoInput struct {
 	IsManualMode            *bool     `json:"is_manual_mode,omitempty"`
 	Name                    *string   `json:"name,omitempty"`
 	PrivateKey              *string   `json:"private_key,omitempty"`
-	Provider                *string   `json:"provider,omitempty"`
+
+	// Provider JSON encoded provider config, e.g.
+	// {"type": "acme", "directory_url": "https://acme-v02.api.letsencrypt.org/directory", "email": "admin@example.com", "key_type": "ec256"}
+	// or issued by the built-in private ca, e.g.
+	// {"type": "internal-ca", "ca_id": 1, "validity": "168h", "key_type": "ec256"}
+	// DNS-01 (required for wildcard domains) is enabled by adding a dns provider config, e.g.
+	// "dns": {"type": "rfc2136", "nameserver": "ns1.example.com:53", "tsig_key": "acme.", "tsig_secret": "base64...", "tsig_algorithm": "hmac-sha256."}
+	Provider *string `json:"provider,omitempty"`
 }
 
 // CertInfoWithID defines model for CertInfoWithID.
 type CertInfoWithID struct {
-	Certificate *string   `json:"certificate,omitempty"`
-	Csr         *string   `json:"csr,omitempty"`
-	Domains     *[]string `json:"domains,omitempty"`
+	Certificate *string `json:"certificate,omitempty"`
+
+	// CoverageIssues Sites using this cert whose hostnames are not all covered, only returned when updating
+	CoverageIssues *[]SiteCoverageIssue `json:"coverage_issues,omitempty"`
+	Csr            *string              `json:"csr,omitempty"`
+	Domains        *[]string            `json:"domains,omitempty"`
 
 	// ExpiresAt unix second
 	ExpiresAt               *Timestamp `json:"expires_at,omitempty"`
@@ -73,7 +188,14 @@ type CertInfoWithID struct {
 	IsManualMode            *bool      `json:"is_manual_mode,omitempty"`
 	Name                    *string    `json:"name,omitempty"`
 	PrivateKey              *string    `json:"private_key,omitempty"`
-	Provider                *string    `json:"provider,omitempty"`
+
+	// Provider JSON encoded provider config, e.g.
+	// {"type": "acme", "directory_url": "https://acme-v02.api.letsencrypt.org/directory", "email": "admin@example.com", "key_type": "ec256"}
+	// or issued by the built-in private ca, e.g.
+	// {"type": "internal-ca", "ca_id": 1, "validity": "168h", "key_type": "ec256"}
+	// DNS-01 (required for wildcard domains) is enabled by adding a dns provider config, e.g.
+	// "dns": {"type": "rfc2136", "nameserver": "ns1.example.com:53", "tsig_key": "acme.", "tsig_secret": "base64...", "tsig_algorithm": "hmac-sha256."}
+	Provider *string `json:"provider,omitempty"`
 }
 
 // CertListResponse defines model for CertListResponse.
@@ -83,16 +205,137 @@ type CertListResponse struct {
 	PageMax *PageMax          `json:"page_max,omitempty"`
 }
 
+// CertRenewHistoryResponse defines model for CertRenewHistoryResponse.
+type CertRenewHistoryResponse struct {
+	Limit   *int               `json:"limit,omitempty"`
+	List    *[]CertRenewRecord `json:"list,omitempty"`
+	PageMax *PageMax           `json:"page_max,omitempty"`
+}
+
+// CertRenewRecord defines model for CertRenewRecord.
+type CertRenewRecord struct {
+	Error *string `json:"error,omitempty"`
+
+	// FinishedAt unix second
+	FinishedAt *Timestamp `json:"finished_at,omitempty"`
+	Id         *ObjectID  `json:"id,omitempty"`
+
+	// StartedAt unix second
+	StartedAt *Timestamp `json:"started_at,omitempty"`
+	Success   *bool      `json:"success,omitempty"`
+
+	// Trigger manual or scheduled
+	Trigger *string `json:"trigger,omitempty"`
+}
+
+// CertRollbackInput defines model for CertRollbackInput.
+type CertRollbackInput struct {
+	VersionId ObjectID `json:"version_id"`
+}
+
+// CertValidationError defines model for CertValidationError.
+type CertValidationError struct {
+	// Code invalid_pem / multiple_pem_blocks / missing_private_key / key_mismatch / chain_broken / expired / not_yet_valid / unsupported_key_type / invalid_bundle / not_ca / domain_mismatch
+	Code *string `json:"code,omitempty"`
+
+	// Field certificate / private_key / intermediate_certificate / content / parent_id / domains
+	Field   *string `json:"field,omitempty"`
+	Message *string `json:"message,omitempty"`
+}
+
+// CertValidationErrorMessage defines model for CertValidationErrorMessage.
+type CertValidationErrorMessage struct {
+	Errors  *[]CertValidationError `json:"errors,omitempty"`
+	Message *string                `json:"message,omitempty"`
+}
+
+// CertVersion defines model for CertVersion.
+type CertVersion struct {
+	// CreatedAt unix second
+	CreatedAt *Timestamp `json:"created_at,omitempty"`
+	Domains   *[]string  `json:"domains,omitempty"`
+	Id        *ObjectID  `json:"id,omitempty"`
+
+	// IsCurrent whether the cert is using this version now
+	IsCurrent *bool   `json:"is_current,omitempty"`
+	Issuer    *string `json:"issuer,omitempty"`
+
+	// NotAfter unix second
+	NotAfter *Timestamp `json:"not_after,omitempty"`
+
+	// NotBefore unix second
+	NotBefore *Timestamp `json:"not_before,omitempty"`
+
+	// Serial hex encoded
+	Serial *string `json:"serial,omitempty"`
+
+	// Source upload / import / renew / rollback / snapshot
+	Source  *string `json:"source,omitempty"`
+	Version *uint   `json:"version,omitempty"`
+}
+
+// CertVersionListResponse defines model for CertVersionListResponse.
+type CertVersionListResponse struct {
+	Limit   *int           `json:"limit,omitempty"`
+	List    *[]CertVersion `json:"list,omitempty"`
+	PageMax *PageMax       `json:"page_max,omitempty"`
+}
+
 // ErrorMessage defines model for ErrorMessage.
 type ErrorMessage struct {
 	Message *string `json:"message,omitempty"`
 }
 
+// InstanceConfigDiagnostic Warning or error from adapting the config on the instance
+type InstanceConfigDiagnostic struct {
+	Directive *string `json:"directive,omitempty"`
+
+	// Line Line in the rendered Caddyfile
+	Line    *int    `json:"line,omitempty"`
+	Message *string `json:"message,omitempty"`
+
+	// Section preconfig / site
+	Section *string `json:"section,omitempty"`
+
+	// SectionLine Line relative to the start of the section
+	SectionLine *int `json:"section_line,omitempty"`
+
+	// Severity error / warning
+	Severity *string `json:"severity,omitempty"`
+	SiteId   *uint   `json:"site_id,omitempty"`
+	SiteName *string `json:"site_name,omitempty"`
+}
+
+// InstanceDriftEvent Last time the running Caddy config on the instance was found changed outside of the worker
+type InstanceDriftEvent struct {
+	// DetectedAt unix second
+	DetectedAt  *Timestamp `json:"detected_at,omitempty"`
+	Differences *[]string  `json:"differences,omitempty"`
+	Error       *string    `json:"error,omitempty"`
+	Reapplied   *bool      `json:"reapplied,omitempty"`
+
+	// ReceivedAt unix second
+	ReceivedAt *Timestamp `json:"received_at,omitempty"`
+}
+
+// InstanceFileSyncResult defines model for InstanceFileSyncResult.
+type InstanceFileSyncResult struct {
+	Digest *string `json:"digest,omitempty"`
+	Error  *string `json:"error,omitempty"`
+	Path   *string `json:"path,omitempty"`
+
+	// Status unchanged / updated / failed / rolled_back
+	Status *string `json:"status,omitempty"`
+}
+
 // InstanceInfoFull defines model for InstanceInfoFull.
 type InstanceInfoFull struct {
 	// AdditionalFileIds ID list of additional files
 	AdditionalFileIds *[]ObjectID `json:"additional_file_ids,omitempty"`
-	IsManualMode      *bool       `json:"is_manual_mode,omitempty"`
+
+	// CaIds ID list of certificate authorities distributed as trust roots
+	CaIds        *[]ObjectID `json:"ca_ids,omitempty"`
+	IsManualMode *bool       `json:"is_manual_mode,omitempty"`
 
 	// LastSeen unix second
 	LastSeen  *Timestamp `json:"last_seen,omitempty"`
@@ -101,15 +344,21 @@ type InstanceInfoFull struct {
 
 	// SiteIds ID list of sites
 	SiteIds *[]ObjectID `json:"site_ids,omitempty"`
+
+	// SyncReport Last sync result reported by the worker
+	SyncReport *InstanceSyncReport `json:"sync_report,omitempty"`
 }
 
 // InstanceInfoInput defines model for InstanceInfoInput.
 type InstanceInfoInput struct {
 	// AdditionalFileIds ID list of additional files
 	AdditionalFileIds *[]ObjectID `json:"additional_file_ids,omitempty"`
-	IsManualMode      *bool       `json:"is_manual_mode,omitempty"`
-	Name              *string     `json:"name,omitempty"`
-	PreConfig         *string     `json:"pre_config,omitempty"`
+
+	// CaIds ID list of certificate authorities distributed as trust roots
+	CaIds        *[]ObjectID `json:"ca_ids,omitempty"`
+	IsManualMode *bool       `json:"is_manual_mode,omitempty"`
+	Name         *string     `json:"name,omitempty"`
+	PreConfig    *string     `json:"pre_config,omitempty"`
 
 	// SiteIds ID list of sites
 	SiteIds *[]ObjectID `json:"site_ids,omitempty"`
@@ -119,8 +368,11 @@ type InstanceInfoInput struct {
 type InstanceInfoWithID struct {
 	// AdditionalFileIds ID list of additional files
 	AdditionalFileIds *[]ObjectID `json:"additional_file_ids,omitempty"`
-	Id                *ObjectID   `json:"id,omitempty"`
-	IsManualMode      *bool       `json:"is_manual_mode,omitempty"`
+
+	// CaIds ID list of certificate authorities distributed as trust roots
+	CaIds        *[]ObjectID `json:"ca_ids,omitempty"`
+	Id           *ObjectID   `json:"id,omitempty"`
+	IsManualMode *bool       `json:"is_manual_mode,omitempty"`
 
 	// LastSeen unix second
 	LastSeen  *Timestamp `json:"last_seen,omitempty"`
@@ -129,14 +381,20 @@ type InstanceInfoWithID struct {
 
 	// SiteIds ID list of sites
 	SiteIds *[]ObjectID `json:"site_ids,omitempty"`
+
+	// SyncReport Last sync result reported by the worker
+	SyncReport *InstanceSyncReport `json:"sync_report,omitempty"`
 }
 
 // InstanceInfoWithToken defines model for InstanceInfoWithToken.
 type InstanceInfoWithToken struct {
 	// AdditionalFileIds ID list of additional files
 	AdditionalFileIds *[]ObjectID `json:"additional_file_ids,omitempty"`
-	Id                *ObjectID   `json:"id,omitempty"`
-	IsManualMode      *bool       `json:"is_manual_mode,omitempty"`
+
+	// CaIds ID list of certificate authorities distributed as trust roots
+	CaIds        *[]ObjectID `json:"ca_ids,omitempty"`
+	Id           *ObjectID   `json:"id,omitempty"`
+	IsManualMode *bool       `json:"is_manual_mode,omitempty"`
 
 	// LastSeen unix second
 	LastSeen  *Timestamp `json:"last_seen,omitempty"`
@@ -145,7 +403,10 @@ type InstanceInfoWithToken struct {
 
 	// SiteIds ID list of sites
 	SiteIds *[]ObjectID `json:"site_ids,omitempty"`
-	Token   *string     `json:"token,omitempty"`
+
+	// SyncReport Last sync result reported by the worker
+	SyncReport *InstanceSyncReport `json:"sync_report,omitempty"`
+	Token      *string             `json:"token,omitempty"`
 }
 
 // InstanceListResponse defines model for InstanceListResponse.
@@ -155,12 +416,90 @@ type InstanceListResponse struct {
 	PageMax *PageMax              `json:"page_max,omitempty"`
 }
 
+// InstanceSyncReport Last sync result reported by the worker
+type InstanceSyncReport struct {
+	CaddyLoadResponse *string `json:"caddy_load_response,omitempty"`
+	CaddyVersion      *string `json:"caddy_version,omitempty"`
+
+	// ConfigDigest Digest of the config loaded on the instance
+	ConfigDigest *string                     `json:"config_digest,omitempty"`
+	ConfigInSync *bool                       `json:"config_in_sync,omitempty"`
+	Diagnostics  *[]InstanceConfigDiagnostic `json:"diagnostics,omitempty"`
+	Error        *string                     `json:"error,omitempty"`
+
+	// ExpectedConfigDigest Digest of the config the server currently renders for the instance
+	ExpectedConfigDigest *string                   `json:"expected_config_digest,omitempty"`
+	Files                *[]InstanceFileSyncResult `json:"files,omitempty"`
+
+	// LastDrift Last time the running Caddy config on the instance was found changed outside of the worker
+	LastDrift *InstanceDriftEvent `json:"last_drift,omitempty"`
+
+	// ReceivedAt unix second
+	ReceivedAt *Timestamp `json:"received_at,omitempty"`
+
+	// Status ok / failed
+	Status *string `json:"status,omitempty"`
+
+	// SyncedAt unix second
+	SyncedAt      *Timestamp `json:"synced_at,omitempty"`
+	WorkerVersion *string    `json:"worker_version,omitempty"`
+}
+
 // LoginToken defines model for LoginToken.
 type LoginToken struct {
 	// Token JWT Token
 	Token *string `json:"token,omitempty"`
 }
 
+// ReencryptStatus defines model for ReencryptStatus.
+type ReencryptStatus struct {
+	Error *string `json:"error,omitempty"`
+
+	// Failed records that cannot be decrypted with any configured key
+	Failed *int64 `json:"failed,omitempty"`
+
+	// FinishedAt unix second
+	FinishedAt *Timestamp `json:"finished_at,omitempty"`
+
+	// Migrated records re-encrypted with the primary key
+	Migrated *int64 `json:"migrated,omitempty"`
+
+	// PrimaryKey id of the key used to encrypt
+	PrimaryKey *string `json:"primary_key,omitempty"`
+
+	// StartedAt unix second
+	StartedAt *Timestamp `json:"started_at,omitempty"`
+
+	// State idle / running / done / failed
+	State *string `json:"state,omitempty"`
+
+	// Total records checked
+	Total *int64 `json:"total,omitempty"`
+}
+
+// SiteCoverageErrorMessage defines model for SiteCoverageErrorMessage.
+type SiteCoverageErrorMessage struct {
+	Issues  *[]SiteCoverageIssue `json:"issues,omitempty"`
+	Message *string              `json:"message,omitempty"`
+}
+
+// SiteCoverageIssue defines model for SiteCoverageIssue.
+type SiteCoverageIssue struct {
+	CertId   *uint   `json:"cert_id,omitempty"`
+	Hostname *string `json:"hostname,omitempty"`
+	Message  *string `json:"message,omitempty"`
+
+	// Severity warning (some hostnames are not covered) / error (none of the hostnames is covered)
+	Severity *string `json:"severity,omitempty"`
+	SiteId   *uint   `json:"site_id,omitempty"`
+	SiteName *string `json:"site_name,omitempty"`
+}
+
+// SiteCoverageReportResponse defines model for SiteCoverageReportResponse.
+type SiteCoverageReportResponse struct {
+	List *[]SiteCoverageIssue `json:"list,omitempty"`
+}
+
 // SiteInfoInput defines model for SiteInfoInput.
 type SiteInfoInput struct {
 	// CertId Cert ID for this site
@@ -176,10 +515,13 @@ type SiteInfoInput struct {
 // SiteInfoWithID defines model for SiteInfoWithID.
 type SiteInfoWithID struct {
 	// CertId Cert ID for this site
-	CertId *uint     `json:"cert_id,omitempty"`
-	Id     *ObjectID `json:"id,omitempty"`
-	Name   *string   `json:"name,omitempty"`
-	Origin *string   `json:"origin,omitempty"`
+	CertId *uint `json:"cert_id,omitempty"`
+
+	// CoverageIssues Hostnames not covered by the cert, only returned when saving
+	CoverageIssues *[]SiteCoverageIssue `json:"coverage_issues,omitempty"`
+	Id             *ObjectID            `json:"id,omitempty"`
+	Name           *string              `json:"name,omitempty"`
+	Origin         *string              `json:"origin,omitempty"`
 
 	// TemplateId Template ID for this site
 	TemplateId     *uint     `json:"template_id,omitempty"`
@@ -195,6 +537,13 @@ type SiteListResponse struct {
 
 // TemplateInfoInput defines model for TemplateInfoInput.
 type TemplateInfoInput struct {
+	// Content Go template of the site block. Besides the custom variables, these reserved variables are available:
+	// `{{.Origin}}` the site addresses; `{{.Cert}}` the tls directive of the attached cert, empty if none;
+	// `{{.ACMEChallenge}}` a handle block forwarding ACME HTTP-01 challenges to the server, needed for
+	// HTTP-01 issuance by the server, empty unless the attached cert is issued by the server through ACME
+	// with HTTP-01 and the server public endpoint is configured;
+	// `{{.TrustedCAs}}` space separated paths of the CA certs delivered to the instance, for use in
+	// `tls_trust_pool file {{.TrustedCAs}}`, empty if the instance has none.
 	Content     *string   `json:"content,omitempty"`
 	Description *string   `json:"description,omitempty"`
 	Name        *string   `json:"name,omitempty"`
@@ -203,6 +552,13 @@ type TemplateInfoInput struct {
 
 // TemplateInfoWithID defines model for TemplateInfoWithID.
 type TemplateInfoWithID struct {
+	// Content Go template of the site block. Besides the custom variables, these reserved variables are available:
+	// `{{.Origin}}` the site addresses; `{{.Cert}}` the tls directive of the attached cert, empty if none;
+	// `{{.ACMEChallenge}}` a handle block forwarding ACME HTTP-01 challenges to the server, needed for
+	// HTTP-01 issuance by the server, empty unless the attached cert is issued by the server through ACME
+	// with HTTP-01 and the server public endpoint is configured;
+	// `{{.TrustedCAs}}` space separated paths of the CA certs delivered to the instance, for use in
+	// `tls_trust_pool file {{.TrustedCAs}}`, empty if the instance has none.
 	Content     *string   `json:"content,omitempty"`
 	Description *string   `json:"description,omitempty"`
 	Id          *ObjectID `json:"id,omitempty"`
@@ -300,6 +656,24 @@ type AuthLoginJSONBody struct {
 	Username *string `json:"username,omitempty"`
 }
 
+// CaListParams defines parameters for CaList.
+type CaListParams struct {
+	// Page The page number
+	Page *Page `form:"page,omitempty" json:"page,omitempty"`
+
+	// Limit Limit the number of items per page
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// CertExportHistoryParams defines parameters for CertExportHistory.
+type CertExportHistoryParams struct {
+	// Page The page number
+	Page *Page `form:"page,omitempty" json:"page,omitempty"`
+
+	// Limit Limit the number of items per page
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
 // CertListParams defines parameters for CertList.
 type CertListParams struct {
 	// Page The page number
@@ -309,6 +683,24 @@ type CertListParams struct {
 	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
 }
 
+// CertRenewHistoryParams defines parameters for CertRenewHistory.
+type CertRenewHistoryParams struct {
+	// Page The page number
+	Page *Page `form:"page,omitempty" json:"page,omitempty"`
+
+	// Limit Limit the number of items per page
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// CertVersionListParams defines parameters for CertVersionList.
+type CertVersionListParams struct {
+	// Page The page number
+	Page *Page `form:"page,omitempty" json:"page,omitempty"`
+
+	// Limit Limit the number of items per page
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
 // InstanceListParams defines parameters for InstanceList.
 type InstanceListParams struct {
 	// Page The page number
@@ -372,12 +764,33 @@ type AdditionalFileReplaceMultipartRequestBody = AdditionalFileReplaceMultipartB
 // AuthLoginJSONRequestBody defines body for AuthLogin for application/json ContentType.
 type AuthLoginJSONRequestBody AuthLoginJSONBody
 
+// CaCreateJSONRequestBody defines body for CaCreate for application/json ContentType.
+type CaCreateJSONRequestBody = CACreateInput
+
+// CaImportJSONRequestBody defines body for CaImport for application/json ContentType.
+type CaImportJSONRequestBody = CAImportInput
+
+// CaInfoUpdateJSONRequestBody defines body for CaInfoUpdate for application/json ContentType.
+type CaInfoUpdateJSONRequestBody = CAInfoInput
+
 // CertCreateJSONRequestBody defines body for CertCreate for application/json ContentType.
 type CertCreateJSONRequestBody = CertInfoInput
 
+// CertCsrGenerateJSONRequestBody defines body for CertCsrGenerate for application/json ContentType.
+type CertCsrGenerateJSONRequestBody = CertCSRInput
+
+// CertExportJSONRequestBody defines body for CertExport for application/json ContentType.
+type CertExportJSONRequestBody = CertExportRequest
+
+// CertImportMultipartRequestBody defines body for CertImport for multipart/form-data ContentType.
+type CertImportMultipartRequestBody = CertImportInput
+
 // CertInfoUpdateJSONRequestBody defines body for CertInfoUpdate for application/json ContentType.
 type CertInfoUpdateJSONRequestBody = CertInfoInput
 
+// CertRollbackJSONRequestBody defines body for CertRollback for application/json ContentType.
+type CertRollbackJSONRequestBody = CertRollbackInput
+
 // InstanceCreateJSONRequestBody defines body for InstanceCreate for application/json ContentType.
 type InstanceCreateJSONRequestBody = InstanceInfoInput
 
@@ -437,12 +850,42 @@ type ServerInterface interface {
 	// login
 	// (POST /auth/login)
 	AuthLogin(ctx echo.Context) error
+	// generate a root or intermediate certificate authority
+	// (POST /ca/create)
+	CaCreate(ctx echo.Context) error
+	// delete certificate authority
+	// (DELETE /ca/delete/{id})
+	CaDelete(ctx echo.Context, id Id) error
+	// import an existing certificate authority
+	// (POST /ca/import)
+	CaImport(ctx echo.Context) error
+	// get certificate authority info
+	// (GET /ca/info/{id})
+	CaInfoGet(ctx echo.Context, id Id) error
+	// update certificate authority info
+	// (PATCH /ca/info/{id})
+	CaInfoUpdate(ctx echo.Context, id Id) error
+	// get certificate authority list
+	// (GET /ca/list)
+	CaList(ctx echo.Context, params CaListParams) error
 	// create cert
 	// (POST /cert/create)
 	CertCreate(ctx echo.Context) error
+	// generate a private key and csr on the server
+	// (POST /cert/csr)
+	CertCsrGenerate(ctx echo.Context) error
 	// delete cert
 	// (DELETE /cert/delete/{id})
 	CertDelete(ctx echo.Context, id Id) error
+	// get export records of cert
+	// (GET /cert/export-history/{id})
+	CertExportHistory(ctx echo.Context, id Id, params CertExportHistoryParams) error
+	// export cert with its private key
+	// (POST /cert/export/{id})
+	CertExport(ctx echo.Context, id Id) error
+	// import cert from a pkcs12 (pfx) or mixed pem bundle
+	// (POST /cert/import)
+	CertImport(ctx echo.Context) error
 	// get cert info
 	// (GET /cert/info/{id})
 	CertInfoGet(ctx echo.Context, id Id) error
@@ -452,9 +895,18 @@ type ServerInterface interface {
 	// get cert list
 	// (GET /cert/list)
 	CertList(ctx echo.Context, params CertListParams) error
+	// get renew attempts of cert
+	// (GET /cert/renew-history/{id})
+	CertRenewHistory(ctx echo.Context, id Id, params CertRenewHistoryParams) error
 	// renew cert
 	// (POST /cert/renew/{id})
 	CertRenew(ctx echo.Context, id Id) error
+	// roll cert back to an earlier version
+	// (POST /cert/rollback/{id})
+	CertRollback(ctx echo.Context, id Id) error
+	// get version history of cert
+	// (GET /cert/versions/{id})
+	CertVersionList(ctx echo.Context, id Id, params CertVersionListParams) error
 	// health check
 	// (GET /health)
 	HealthCheck(ctx echo.Context) error
@@ -464,6 +916,9 @@ type ServerInterface interface {
 	// delete instance
 	// (DELETE /instance/delete/{id})
 	InstanceDelete(ctx echo.Context, id Id) error
+	// export config and files of instance
+	// (GET /instance/export/{id})
+	InstanceExport(ctx echo.Context, id Id) error
 	// get instance info
 	// (GET /instance/info/{id})
 	InstanceInfoGet(ctx echo.Context, id Id) error
@@ -476,6 +931,15 @@ type ServerInterface interface {
 	// regenerate instance token
 	// (POST /instance/rotate-token/{id})
 	InstanceRotateToken(ctx echo.Context, id Id) error
+	// get status of the re-encryption job
+	// (GET /security/reencrypt)
+	SecurityReencryptStatus(ctx echo.Context) error
+	// start re-encrypting all stored secrets with the primary key
+	// (POST /security/reencrypt)
+	SecurityReencryptStart(ctx echo.Context) error
+	// list sites whose hostnames are not covered by their cert
+	// (GET /site/coverage)
+	SiteCoverageReport(ctx echo.Context) error
 	// create site
 	// (POST /site/create)
 	SiteCreate(ctx echo.Context) error
@@ -677,6 +1141,109 @@ func (w *ServerInterfaceWrapper) AuthLogin(ctx echo.Context) error {
 	return err
 }
 
+// CaCreate converts echo context to params.
+func (w *ServerInterfaceWrapper) CaCreate(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(JWTAuthScopes, []string{"admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CaCreate(ctx)
+	return err
+}
+
+// CaDelete converts echo context to params.
+func (w *ServerInterfaceWrapper) CaDelete(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id Id
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: false})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(JWTAuthScopes, []string{"admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CaDelete(ctx, id)
+	return err
+}
+
+// CaImport converts echo context to params.
+func (w *ServerInterfaceWrapper) CaImport(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(JWTAuthScopes, []string{"admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CaImport(ctx)
+	return err
+}
+
+// CaInfoGet converts echo context to params.
+func (w *ServerInterfaceWrapper) CaInfoGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id Id
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: false})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(JWTAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CaInfoGet(ctx, id)
+	return err
+}
+
+// CaInfoUpdate converts echo context to params.
+func (w *ServerInterfaceWrapper) CaInfoUpdate(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id Id
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: false})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(JWTAuthScopes, []string{"admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CaInfoUpdate(ctx, id)
+	return err
+}
+
+// CaList converts echo context to params.
+func (w *ServerInterfaceWrapper) CaList(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(JWTAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CaListParams
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CaList(ctx, params)
+	return err
+}
+
 // CertCreate converts echo context to params.
 func (w *ServerInterfaceWrapper) CertCreate(ctx echo.Context) error {
 	var err error
@@ -688,6 +1255,17 @@ func (w *ServerInterfaceWrapper) CertCreate(ctx echo.Context) error {
 	return err
 }
 
+// CertCsrGenerate converts echo context to params.
+func (w *ServerInterfaceWrapper) CertCsrGenerate(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(JWTAuthScopes, []string{"admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CertCsrGenerate(ctx)
+	return err
+}
+
 // CertDelete converts echo context to params.
 func (w *ServerInterfaceWrapper) CertDelete(ctx echo.Context) error {
 	var err error
@@ -706,6 +1284,69 @@ func (w *ServerInterfaceWrapper) CertDelete(ctx echo.Context) error {
 	return err
 }
 
+// CertExportHistory converts echo context to params.
+func (w *ServerInterfaceWrapper) CertExportHistory(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id Id
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: false})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(JWTAuthScopes, []string{"admin"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CertExportHistoryParams
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CertExportHistory(ctx, id, params)
+	return err
+}
+
+// CertExport converts echo context to params.
+func (w *ServerInterfaceWrapper) CertExport(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id Id
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: false})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(JWTAuthScopes, []string{"admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CertExport(ctx, id)
+	return err
+}
+
+// CertImport converts echo context to params.
+func (w *ServerInterfaceWrapper) CertImport(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(JWTAuthScopes, []string{"admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CertImport(ctx)
+	return err
+}
+
 // CertInfoGet converts echo context to params.
 func (w *ServerInterfaceWrapper) CertInfoGet(ctx echo.Context) error {
 	var err error
@@ -769,6 +1410,40 @@ func (w *ServerInterfaceWrapper) CertList(ctx echo.Context) error {
 	return err
 }
 
+// CertRenewHistory converts echo context to params.
+func (w *ServerInterfaceWrapper) CertRenewHistory(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id Id
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: false})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(JWTAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CertRenewHistoryParams
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CertRenewHistory(ctx, id, params)
+	return err
+}
+
 // CertRenew converts echo context to params.
 func (w *ServerInterfaceWrapper) CertRenew(ctx echo.Context) error {
 	var err error
@@ -787,6 +1462,58 @@ func (w *ServerInterfaceWrapper) CertRenew(ctx echo.Context) error {
 	return err
 }
 
+// CertRollback converts echo context to params.
+func (w *ServerInterfaceWrapper) CertRollback(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id Id
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: false})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(JWTAuthScopes, []string{"admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CertRollback(ctx, id)
+	return err
+}
+
+// CertVersionList converts echo context to params.
+func (w *ServerInterfaceWrapper) CertVersionList(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id Id
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: false})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(JWTAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CertVersionListParams
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CertVersionList(ctx, id, params)
+	return err
+}
+
 // HealthCheck converts echo context to params.
 func (w *ServerInterfaceWrapper) HealthCheck(ctx echo.Context) error {
 	var err error
@@ -825,6 +1552,24 @@ func (w *ServerInterfaceWrapper) InstanceDelete(ctx echo.Context) error {
 	return err
 }
 
+// InstanceExport converts echo context to params.
+func (w *ServerInterfaceWrapper) InstanceExport(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id Id
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: false})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(JWTAuthScopes, []string{"admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.InstanceExport(ctx, id)
+	return err
+}
+
 // InstanceInfoGet converts echo context to params.
 func (w *ServerInterfaceWrapper) InstanceInfoGet(ctx echo.Context) error {
 	var err error
@@ -906,6 +1651,39 @@ func (w *ServerInterfaceWrapper) InstanceRotateToken(ctx echo.Context) error {
 	return err
 }
 
+// SecurityReencryptStatus converts echo context to params.
+func (w *ServerInterfaceWrapper) SecurityReencryptStatus(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(JWTAuthScopes, []string{"admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SecurityReencryptStatus(ctx)
+	return err
+}
+
+// SecurityReencryptStart converts echo context to params.
+func (w *ServerInterfaceWrapper) SecurityReencryptStart(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(JWTAuthScopes, []string{"admin"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SecurityReencryptStart(ctx)
+	return err
+}
+
+// SiteCoverageReport converts echo context to params.
+func (w *ServerInterfaceWrapper) SiteCoverageReport(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(JWTAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SiteCoverageReport(ctx)
+	return err
+}
+
 // SiteCreate converts echo context to params.
 func (w *ServerInterfaceWrapper) SiteCreate(ctx echo.Context) error {
 	var err error
@@ -1283,19 +2061,36 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	router.GET(baseURL+"/additional-file/list", wrapper.AdditionalFileList)
 	router.POST(baseURL+"/additional-file/replace/:id", wrapper.AdditionalFileReplace)
 	router.POST(baseURL+"/auth/login", wrapper.AuthLogin)
+	router.POST(baseURL+"/ca/create", wrapper.CaCreate)
+	router.DELETE(baseURL+"/ca/delete/:id", wrapper.CaDelete)
+	router.POST(baseURL+"/ca/import", wrapper.CaImport)
+	router.GET(baseURL+"/ca/info/:id", wrapper.CaInfoGet)
+	router.PATCH(baseURL+"/ca/info/:id", wrapper.CaInfoUpdate)
+	router.GET(baseURL+"/ca/list", wrapper.CaList)
 	router.POST(baseURL+"/cert/create", wrapper.CertCreate)
+	router.POST(baseURL+"/cert/csr", wrapper.CertCsrGenerate)
 	router.DELETE(baseURL+"/cert/delete/:id", wrapper.CertDelete)
+	router.GET(baseURL+"/cert/export-history/:id", wrapper.CertExportHistory)
+	router.POST(baseURL+"/cert/export/:id", wrapper.CertExport)
+	router.POST(baseURL+"/cert/import", wrapper.CertImport)
 	router.GET(baseURL+"/cert/info/:id", wrapper.CertInfoGet)
 	router.PATCH(baseURL+"/cert/info/:id", wrapper.CertInfoUpdate)
 	router.GET(baseURL+"/cert/list", wrapper.CertList)
+	router.GET(baseURL+"/cert/renew-history/:id", wrapper.CertRenewHistory)
 	router.POST(baseURL+"/cert/renew/:id", wrapper.CertRenew)
+	router.POST(baseURL+"/cert/rollback/:id", wrapper.CertRollback)
+	router.GET(baseURL+"/cert/versions/:id", wrapper.CertVersionList)
 	router.GET(baseURL+"/health", wrapper.HealthCheck)
 	router.POST(baseURL+"/instance/create", wrapper.InstanceCreate)
 	router.DELETE(baseURL+"/instance/delete/:id", wrapper.InstanceDelete)
+	router.GET(baseURL+"/instance/export/:id", wrapper.InstanceExport)
 	router.GET(baseURL+"/instance/info/:id", wrapper.InstanceInfoGet)
 	router.PATCH(baseURL+"/instance/info/:id", wrapper.InstanceInfoUpdate)
 	router.GET(baseURL+"/instance/list", wrapper.InstanceList)
 	router.POST(baseURL+"/instance/rotate-token/:id", wrapper.InstanceRotateToken)
+	router.GET(baseURL+"/security/reencrypt", wrapper.SecurityReencryptStatus)
+	router.POST(baseURL+"/security/reencrypt", wrapper.SecurityReencryptStart)
+	router.GET(baseURL+"/site/coverage", wrapper.SiteCoverageReport)
 	router.POST(baseURL+"/site/create", wrapper.SiteCreate)
 	router.DELETE(baseURL+"/site/delete/:id", wrapper.SiteDelete)
 	router.GET(baseURL+"/site/info/:id", wrapper.SiteInfoGet)
@@ -1321,47 +2116,108 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xcW3PbuBX+Kxy0j3Kk7W47U72ldptqm6Y7djx5yHg0MHkkYU0CXAC0o/Hov3cAAhQJ",
-	"8SqZCqPhS2KJuByc853LB4F4RT6LYkaBSoHmryjGHEcggetPJFD/BiB8TmJJGEVztLhBE0TUXzGWGzRB",
-	"FEeA5qrtBAl/AxFWnVaMR1iiOUoIlWiC5DbWraiENXC0201QSCIiDyf4qL725AY8mkSPwD228oiESHgx",
-	"cC/Ga7AC/JEA3+4lSMfLCxHACiehRPOfZrNJG5H06AcSfd6AntcIVDG9kayDCna2tVb2+yAgakIc/ouE",
-	"sKArpv7XRuEsBi4J6HY+oxKoLMzwSCjWspg5hOSErvWSzDfs8XfwJdpNSuZZ0DiRhxOtSAjp2l7dce2i",
-	"X4+b8AuRm8WN6o7D8H8rNP/6iv7MYYXm6E/TPSCnRjvTKpF3k/p+qQxmtt3DgSwfiZC3IGJGRYmiM3y6",
-	"hlPQFfqJxqX6o5v0VqJMWZhzvEUGgMsIf2saM2tXqvFr4LLGsL76c0V8LMtt6wte+n3AIkyoKCz8oJG7",
-	"IqU1HkFAsIRl08xELCNMExwuIxbkmzwyFgKmNciboJiTZzXJE2wrnrNnEgBvCVurxK5gLSq/G0Qnrqng",
-	"W0w4iCWWTYiQJAIhcRSXrebBrOc8gHc01xvM/8k54/8FIUzULq4m2j+AbziKVSxF9/SJshfqgeraLl4u",
-	"qJCY+mlETsKwPRLyPfdocHSOhVwKAHqyfQ9nO1AJziLRUgX3JQlEWYL3lLVV2t2391R7oTJfGwCkYpWb",
-	"/kQXh6XP6IqsSx8LIpsXpRq9wUqaoNI1bBzArHtyc6f/zJ5SXHWXoDIgSTtmo+fkJTpP3CldQE+x5yNb",
-	"E5opuEJHRQz++uWzl/ZoFXfuiISGJL4sK9BV9PUWN96KcU9uiNCAR83lb43bMU7WhJane4jiEGu3Kymc",
-	"zcPjxMmGfsZhAp3Kjjp9dnXMoh26e6Xqfx78OyvsDfvWrHXo3JOUwzIyj5H21GKCnjEn+DE8HQr5BXSF",
-	"w+Hiu0PCjnEeWJSstjdo3Avgap5rDqbGb6dV2y/LfEVVxFiIF8aDdqX7K+LwR0I4BGj+FSUCuIbUZD/M",
-	"gzZDYdLOolZVdEQscRAVwmWutMmkKZSl15gGhG5xm8yQl7zC+w4n+LTFnpqk5R6BHb+rcxyYsZtfqO7n",
-	"8Qlngb35Q1Y/ttgPsq33SneAFbSvVstkya8ok4VQ+bdfytNvRjgO8npCyTdPgM9okM/kVWOpyhz8hBO5",
-	"vVOSpsv59cvn94ncqD+1/NpRAHPI8bKNlHG6UUboimm7E2k8Jgi23g2E5Bn41vsE8oXxJ+/Ke698z3v/",
-	"2wJN0DNwkUo8ezd7N9MqjoHimKA5+vnd7N1POijIjRZouqc7V4ruTP0siMUsxZYyB1ZNFgGaO5tJJuRN",
-	"dPABIf/Bgq2TCqMklCTGXE6Vzq4CLHG2fNz/dljFDuPuYadVzI3XaWX8ZTZzhMdxHBJfr376u0hz917y",
-	"4zbAdm45gFItBp5IfB+EWCVhqN3xl9nPbyZPYd+gRIZPzIuBR0Ro8OThq02TAfcrSgP9w+5hgkQSRZhv",
-	"0RylsPEcNClQ47VIexWfPKgpDtAXQAgSpq8k2KUeqD42gfAmbTUpbORXwGnfZEoCpNZQhoCiatLxB2Ue",
-	"Nfkv55xcJP5G74d0h0ZqxNOhwV5oyHCQgWMNjeHpxvR5S2xUaJz5EuSVkBxwVNR88w8WBwr/AHJEWxPa",
-	"XJgZW58MNJV1u4BMxfYPIM+Asf4y0Ai47oBbg3Sx5umKrQ5wuvTyN20wdR8H+JS0VlWQvSWiTA02nFIq",
-	"VdqYq4/M1YlWX3dYl8VRy01bhFDFfzsDXZ89qCr/c+1S+nxyqHU5+YB/DB98fD8pxoYpWLqBkUMcYn/P",
-	"LNqQ29u0z9tH4N4o8fCo7RiPT4rHBrbda9pEbqYhMz9gVYA9kRv9ix46vlpovWNd3ARu3I3tF8C53zFL",
-	"LHaXQjU1VmaJ0Ggq07syW6psH7hs3De7Bi5b7JYdvyjnCE6/GnRPu1zynpYyb87w+mPO8C23rJTGxo2q",
-	"MwVbbaNjN6oa7N28PWCdY4ibAs2OO24FtABQSZmqurhEyWCnkvRbawyP6g8sm4xl5EmRzdD6Gohm4a2W",
-	"tdvjtBfM1c9zjveyGLrGlUPLXVxxoPDSQL+V7m9Vu/5KJD38GEg2nqJjx/BRCi81FdIGcJgebyiNH//W",
-	"j6834D+hNtbKqNgE/fUN802TjhZUKq4aegL4M3BzcL7IB9OVer5Zi9WFUUCqDWLO5jaSQ3uIt1eCWHIy",
-	"v9+0Xn40+6K5orV4DhHZVw4mWvJGq8SRO54pMGb2OpY/dsBAM5fM+9AQ+WRrHx9pZUtsldRXtptbu+dg",
-	"VUkx8wYaHs387jlppJs9BUNDOVtAtxARa+ln/s2qC6ag53ul67JoaIY1h4pWYY0ziSVc6TfXGpipNcmt",
-	"7mJfaPtBU/F/YOulax8j3AkRjsMaqAJJLspJ+6pjOfQEkc1k8I7Ifomg83JfvwnXfT/vktmfecvTGl9/",
-	"zBm+JeNTGhvZ3pncX9voWKbXYO9mdmedY4jMrtlxR0rXAkAldYrq4tbDBjuVNM5aY3gUbmDZZKRuJ0U2",
-	"Q9tqIJqFt1qqZi8AGDpNawJb4c3cC+NL2sgOV8oZ2V5F0Viw2nftey1aS64g6DfUlN0gcMnFqzV3DgzZ",
-	"Vw4gWhayVoNjMXumkJ/Z69iCtgMGmovbvAMNscBt5+BjkdsSWCUJxnZzK4kcpioL3rx1hlf0DjQbjcXv",
-	"yZHQFMAtoFsIh7XFcP7qowv+3eJ8dy5dVh2eYc2pxR2sJQJ4Yy1+L4D3Woc7l131HPbcK4t+iAJcTf73",
-	"s01+b96r8nxGVyHx5dEMgMJLeixwD0H9MQe/lpW/kmms+gd9ktNU/A32tjdQlaY165wfQN5BuELfNQ4c",
-	"JoBueyEQrtxcX6aMesqT08jg6M4xKhy9qQ12VJcK7FTSG2uN4VEb56rH753fR0pzHDANj6nBZhbXarmL",
-	"vZ7yR97IP7hi80cmEOXpPItDDodwbW1f0d+fd0oqjP6bafl9ItQJV+FWRqwxsLxhYMlMUo01zkJoxtkt",
-	"C2EIGKu7wvjcF1SMabFn9mNArBBaA2B7ZUkziC0JHwKQB3TRyojjjjge8NZRdX1pLpp3vUgPxZ+tCyQ8",
-	"RHM0xTGZpi65e9j9PwAA//9Cr9wbim8AAA==",
+	"H4sIAAAAAAACA+1de3PbNrb/Khjd+0c6I0vOo9ludnbmuk62zW6bduxkOzt1RoFISEJDgVoCtK16/N3v",
+	"OXhQfIAiKVuK7Gg601gSQRzg/M7BeQA4N70gni9iwYSSvVc3vQVN6JwpluhPPMT/h0wGCV8oHoveq97b",
+	"171+j+NfC6pm8LeABvAJnu33ZDBjc4qNJnEypwq+T7lQ8ItaLvRTQrEpS3q3t/1exOdcVTv4Cb8masaI",
+	"SOdjlpB4Qrhic0kW8GFBp8wR8N+UJcsVBeZ9eSJCNqFpBH08PT7utyFJv71C0XugBX+xBNV0bynrMAW3",
+	"7mk92SdhyLFDGv2DR+ytmMT4r2ZKEsPQFWf6uSCGFwhV6GHMBdW02D6kSriY6iHZb+LxHyxQPfii2s9b",
+	"sUhVtaMJ/GzGdlN+rxv0zWYd/sbVDHAEzWkU/TLpvfr9pve/CYM/ev8zXAFyaGdnWEfybX99O0OD7e32",
+	"Y4WWn7hUZ0xCM+mZ6AyfZcYhdKX+ReMS/+hGvaMomyyaJHTZswAczel10zuz57wzfnpymjCqWMbYdtN8",
+	"elKc2jLu5vNYjGoR8ZktR+bLsvyw4Nm3L8kTK43fkCFhwfPvXsC/iaTPjl98Z/56cfzXl1UI45wkQObI",
+	"KKNmdpuJvaQRh2lfVqn5ISZhmlD81CeWJklUTL77y8vj4xkBoSJJHCtCRUhePP/OfYfsT+Ys5DCxXklL",
+	"2H9TnjAg8/fCZH2scOij5tFbGECi7pdH+OeEB0iij0cbTeUi4ZfwwhEwuDqb8cJAu08oCSi5AmTHqSK2",
+	"DYE28LUgsYiWZMxIyJGYcapYSKiENipJpdLz3TynucHVzmm9Ouugscx7umqpMmvaa6bOfGTXC5gVOaKq",
+	"iZGKz5lUdL7AZjMqRyVu2leP4zhiVGyIkdsabuxGvxa4tT2dCoSfnp/VgCuIU6GSpZdZYTynXMjCeCoP",
+	"lYnemjaN4gBUo1p2WdX7vTiZUsH/pIaGhgdoNEpFgbU5DZTEl1wENZKYF3c3bR9rmPHmGtXnj4CUOFnu",
+	"AGVZl2csiJNwu0gr9FQZE9O/srCr/Dt70cOXbksCX3hfkkqW3FFxFIcPJrb0WabZOBiY5AiWBZsD2P8E",
+	"uoALnwP59FkON/n1T8orO6VFqXLI00u9fUPTgmTJqANoaX3f3IpfI5f1A3K/oPuEzpQZExmnIoza2C+G",
+	"vNqx1a+zTWtXIJP7U5N5o2zU1DOXIHQiBfU0j0PmX/3qZ9q7bpYUW8iSKif+ef7LO8JEAH2GxD1GYIYn",
+	"fNonbDAdXIibC/3Gi94rctGjwRz+6sNfIfAjQP02SpPI/DhTaiFfDYf40NHl8bMBXfBBxJSEHpLlQg1A",
+	"Ew+zduY1IHXcNqfhnIv/Y9egESI2AOE0T7jlxjykl5iL3u2FQLtXyhQIHy81jMYpj9QRF5mFF1DvGDRj",
+	"YCU4CqjpIaCgGfC3p/jJmefm4acvv5utp+P1u/Oj46fkSUFMr3gUBhQgbqHzDdAKE03HkaGXguMlpmBk",
+	"hkLWTTzMsZDYW4H6ZBI8e/r8paEJIQGK7ZIl5kchnw5yE/jq2+fmOSX5FOGxYuIg94NkQcKU+W1MJXv5",
+	"YjDI/06jaZyADTO3bJ7T4EjOKEzAQM9AO+feyWZn27Ug03e1XmOYK1ziNHRkVSLOQcAlSSVyR82AaSi5",
+	"5GoWS0bgf0rPOAE7lAj0w6KI6FeysG8cCZjINBHA5KsZEyRdhGB2wIz0263k2PupJfEtUuhTLBtZ2DV2",
+	"MAxuR5ZwkftbtVDOmGBXO7W+dI+7ML7yHVVtrySJ/QvYhAsuZ92tsm6mFzTbxPSTaRAwKf2LHgxhOvUt",
+	"XWa5JKBr8aVhCoq1vR46i6NoTIPPNXYCCKCEXrrai3krJfeKOkPl37jQaK/kjWNcWVmFHieLC71CjcCu",
+	"BHdqDo4WB3WPH0dj8KA+S/wW1BtMQN6jhm9xBYNfwKILZvAxmMHKNBon8WfQVOCqabUSwl+g2UZLpka6",
+	"H/icCpkurFnvVkH42hFi7DbbLqDwh1n0sr56qLP0ogTk54nw2ZMTziKPzZgzoKCD4rjqLC0cozEWsYmL",
+	"HWT0yQJdeSPKQxbgVdoIfKWNDiWFMawLuCaY6UWDJG/0tYZmCRU/r/r1CLvspKXKgPNoKu8wv6chcQ5P",
+	"+3EYAfCgWsd/O2uJzWzwbq6jHAVpkljfp4g/WM6BpUnGV7TnclaCFXcAwNVqinJqTJsbft2MUkMnyvza",
+	"ejqw1ZiBqcm66VqWcBpVhzdj184P8KFfxmkSeHRRuohiihLFtUOJ4R1coPBfq2HhTynoQs58UdS+U5Mt",
+	"03LrgLY7M8Yhe2sL/XrB94roB/EZoCeIMQFaCelbAagQAdib6HS85nQqwL7lQZXLv9FEINJhrdXvJ5Mk",
+	"noMLQxfKCACznguYwPoTt6/u9Uu0G+ePX/r92IgL5ku6CnyjfjGgK0Rjm5yCA7XELKAHKAUtVoUyEmAQ",
+	"V4pKAGlmFABZ7lPZWePRGkoTFlEcIaZtkGRtFLlIh+vcR7RkIAzetJCZ9CG5MnzwEgYEW3OlSY7swx1y",
+	"Dg4prxM+UW8uvfrxJyoVQVVj+JQKjRjNphpwwHAk+MpgPKAlIqbA1TgFfzNkbrau4uSzzmmXQMQUELbB",
+	"CsInEwAPdN1xFam3q2EpWywizkK/8QqIYgCF8K6u2ooFmKY9X4oANJ3ePXBTEbCpDUtWEzO1o9C7Jbyy",
+	"oqhKPU5yKhzHhsbF1X9NKI/0H6j7YdCo/rupIr2zII2i9sGBfMu6zGME0BxJxkS3lRJmeZQwXNaa2jki",
+	"DGN0ixp/u0puhYM0S8mPUL+BUEvfTheCyxYKyup5gs/LtqGGvOFTBrwOiK3vNm9m01TNMEAEIyhnUVc5",
+	"1Hsh7I5hUvAOtCryg92o0PXDxofuYSRNQtA1RlYRoO7bT8rdv0e3cDMKaqNvyr2zUSfkKdqNZecdwJYM",
+	"PI/C8C+oqITAokBdT4wuWoW7a1bHAJfcEZrloL1Wk1bNdejncvZ39QktK6PVklIk8LX+3q3Vdo3HfnEh",
+	"r9iBdW/nYoSD9ItymBmlsjMfK2Ztp4WdXS+MjbHJJBhLD6PyxPqTOjCMpqvU2YGmuTGKvOuIS7aBZ7x6",
+	"JQzRimv7zpzJt7E9U29HxJ8zs8Fr1MJgundm5GINsn0S+VM85SJTeTVaq5Q7++09MS1a2ThnzGbBzrPJ",
+	"aB29NTPkSQxjHBgW2RlVuJUJo0+4kYnpfjAHAXqMUOEs8BQ9JxPfypwEUJcvX3i9hE1jxnM+TdAirKc3",
+	"YUd2LhyNOguc8DlNlu0JtA38m794ll3G4FwqoSdwyGy3XrBtGLxWNqVb7l5HRJ0fhCFHwdaCXcXKF5hx",
+	"cwZ9B591y8aZ8aEvn1haH15YJcbuL2N1P1HFalfeBH9rN9il84pkjfMJ1IZIsCc+UOfDW9+dPJHx3JdJ",
+	"tFlEvWdKu/tPBCLGYnj1PGYk7aOF8PWXig3kmWJMmXWmWgd7rAWw6uhp2AEy8p0dwMAeAUPfLM8wyzYE",
+	"1Dxjazam8Sn3m1YwAYuIZmwp7em3P25GTvbqSxql3SId6+azq0dS5MO2s/Y/ZvKRkyVnKCPPvdl5SS/v",
+	"Nzfv92Ow4W58mBKztua/OISuE7TVJrLKHncH0SwqCmQTnbsckO8ZRgClYVwqVTwnlzThuHFG9vFbiSFW",
+	"bV2Hq1+0GqWXsLjix1cX4tPNzeAXLX+3t59WnYDPA40lk38j+AQKvftdRRi8sOFpRxlVimJ22WII6FZL",
+	"wicEdfPfTC8npz+/OZ2BUDAxZfgySmZUJ0T1iFCCQTfr3T74KPnx/ftfcb9Q4NrILFKsXYY+EYyFZhvR",
+	"hXBPI/B10NRi2j1rKEoFzIGsUoyrRXGPlHVL1CyJ0+lMU3QhtAnmesKjBbknF+k44gEYTuEiBlia9ccZ",
+	"k3YK3mOQh4WnJxLHLxc0wOZ4XgsNPIwuSjehpyeaMJhqFnEjpXb0zh/qa50HBht8A68Htox0EGm0AL9Q",
+	"h7hIucscYwoB5hmVmlMD3xalfhGXXXYcZ7C7q3LNy1FXBVuVwe4xH/eO3Wgnz2i3pqE+AHyxH3PYqP2s",
+	"unZZEK04Ffk9rc3G0U1hRwjuQNaQym2N/ajZUOi0M6l1YW8uR3pHpT+0klFTMH5PQfq5WNI2Bnme8oZz",
+	"LasO3i0pwU5aHgh07+8qHBU2dpMLbL4bmSgNcGvykIWiWzkBhRmpeoh33EWfH1ELN3/lbnvyUPwac6qx",
+	"aOsYw8MpOmjnSKkZzj9/e3+SmgSYpl8LCgODIpdHx53N5lQsB2ZpvnNlJQYznK/NarYk75jC+BM5Iico",
+	"e+Tk17e93C6H3vEA/tNTvGCCLjh89Ry+etozWThN0HCV0jnC9W4YZEpsERtsITv0Rp63wIvSyVGr8sx2",
+	"NJi57+NwWbLIzKYxmqghztlRSBXNhk+3f/a15jgxiN6t2UZnpE5PxrPj4xLxOuMa6NEP/5Bm7V5Rvtlp",
+	"19uyOdAzsxgSuzlxAopEi+OL4+f3Rk8hEOOh4V2M58r1Xj7cbpKDr2ZNBtzfzdZ5XE1wN+Uco2K4ZU4P",
+	"gZTQhKCmU2laFX/5iF1U0AeWGlNseMPDWyOB+LEJhK/NU/3Cqf0aOK0eGYJqwTH4EFCKu+v37xV7sPMX",
+	"u+wcxj7TBnF3aBgm3h0a8ZXAjE8GjilrVE+vbZv7xEbNjMeBYuoI7AtG58WZb76doDLhPzB1QFsT2sow",
+	"s7y+M9Bw1e0CMtTtwLAdYGx7K9ABcN0BB9AoY41oi20d4LTpFczaYOqD3uy0OazqDLL7RJS1wfbHlPpg",
+	"d4gdwLzRWm022HWHtU+POt+0hQpF/7cz0PVFQ3Xmf+454z7fWdXeIb2065tv9l6/30nHRgYs3cCYsEVE",
+	"g5Vn0ca5PTNt7l8Db80l3j/X9qCP76SPLWy727Tw0mEU25RwDdjhGb0Vqbe5tdA6Yl0MAjdGY7cL4NwG",
+	"LA/Hzu0RUc2sjBORnals3pFtZrID2hg1O6UtImWbD6h40Zl39p7eY2ebRrOOdyZ1b81xUTBcYELwLJE5",
+	"kek2rnGXUn9AEbYpE4go0AXmWrbSVWzeTfLLHGADuoJryzDbKT2E1na0PAB3sOenz3bW87niUWQ2K46X",
+	"JJjxKAQqZN/mzDW8TG5bbhzz64JJc6pznQo1FwltTYXm7yn6wirUkLJlHbrmALhPq+dYeZm1cntMH5Yq",
+	"tQeIqSDsGpwJ3C7TCamNAULA6p4GBZuQdwgEWmXcyUX1oqccNdEQqo3/GcjsX8yvcJvnds3yJmwefMlW",
+	"+Fwb2WuPVKvs1kbxTulDiNytx1xh780jC535uV0KoK24DU83e7J4/+tWfdniFWxbVjmlm8K+jDf79Vpi",
+	"dtsIIi+PSPyYx6S5KtMBskjBCbG3c+Ep7Wwnsr34Z3Xiy7nPYf5O7n62Axm6wGbZnn3cFyzTsR0Y7hvG",
+	"e70HF+IX3Owr+VTgLXz2Shq93b90CRN5gq+u89C/MYQFFn5mZetfCK7wNscIT9bh4Z4Jx5PtU7yLSNkL",
+	"PuKkOASz39gjozL5wQ55i4Ka3QS9bYepUU5/yPi7D3Ene4EUetF4JFBHNx9suCl/8xiCGmXFHro2G/fX",
+	"iG7bSBM8e4g17cp8ZPbSkE1DOmv4ba7kPpqZCzEbvOTy7eUb8b7/oMzN2gvbH7LlWadBFDFwIO5gsb3L",
+	"pRE/lTRt6fYsNn9FcEr0rZKFVXcSR1F8tTqDlNddHOtvMJ2Jg1XzT754pZsOzOWW+l327+zd9jO+Bdda",
+	"+AQtzRXir0h2tfgiic0tUfZa8f7qJ21TmHMZdSu1wcNe+fyVq+c7m+H3uh3yzfUOwqFtl3ZDP67s9uLT",
+	"jNlf8WKCff91Z33r89PmwF1B+DHPl5P3JdtgmbMay9zEjTY6VzL/1jW6q5rBqNZPs/fX4iFrmB2FDkJh",
+	"DP01ST1R9F0uhHY5tArUagYgqA856hsT8beI0YlxcZw96m7exQu99TlWkM9Sgzo11SL70rivpdHE32EK",
+	"ptGj2MMkjNNBFkWA9+DxpWW06JlbP121jieLyfU3RuVeo/sLi/KqgEedNDZnaSwE9jJP08LhPWRqGl2Z",
+	"mphoJeBtsFOfnCnEafbNVNujaGl9iuYQLf3qLTYwUsYMDWZ9bzfe3JfY61uJvr71LjmtGonOVoP1aSxb",
+	"meQRb0HfTUmUx5c9qyTLSrjSUG4f7spXi/k6ol3e+jiPDCimDgFVeNeQahPl0g0aziJkk/cAbdMzq+AP",
+	"9um+rLVIxLf36D63JYKLfHrWULG7Pa7/MLfEq9js+M65llmZwg2OZKCwNwm4rUjSEMh+r8tM2KSuKx+E",
+	"sSF66QpDY2f2Sg8Tysl25VYKx8FL2CKKl4SrgTeE4ypR7ZsDU6yQ9cWdmLOVTbpHjgxixVX+sdUPcv4M",
+	"mNIiJlEsprq0Iz6Y3URXQcpXq4pxmi5XFXX2QCt31j4xVoHEd2l44r4UQRhNIs5WQ6vXS/YJ2Wyp5iod",
+	"fR2Gqq+00yHsdm9hN6e6rKu03kieMRqZW7K8+PxR/3yKt1T32uz8yE70ofWxy4yhqf3rrrc0l64XjxWa",
+	"kZobt3NzYSfAzIZb8Rv3ZmblCLa5P9NTBWe7C7a/WMijvkYrX6/BIiL7qoSJlhusshoLh01Wu1GRGb82",
+	"3WjVAQOlTTNWZ5ZdctzZih6Foslg+iehSTDD24dxp6vxG1x9vexO2/FSXy/8qlgO71NWDw8vw9U3EWqD",
+	"BJ68EKUKUSTFRjqH/gnTwcMgFMPBYPBJ39TbJ5/Ofzx59u3L8w8/n3/S3g1YM590/1E0kLNPxAzBl5N2",
+	"iL7r9pnWWmv6J1/ceStLbik6iETnTRmm5A3iRFetQSOig5g0Z4TzS80+ZoVbL4UHK7Ul3jyWaqb+Siml",
+	"HKxqE8V5Bu1fsviLm26Hc31bUpA2E9oCugWNuDYrmi+J94gzo7urxfe4kl4Z1koZ0jqsJTFWrzrSBc4a",
+	"Ul+OJWe6iat79kCX4n+xJTFjP2i4O2i4hGUnkjLkKVcRzw899/5h4srh1eq6c/touXDeFgFU7upxHgAx",
+	"1RhdRZZVMT4MBf4Rj3PMy16tzStvugy4lDBwJUsF/WJ4tT5nZ0rzrEoSoqOwelafo/SWAMRjnVFo3hCA",
+	"BzpGSufxpdtL/ebd6dl/fn0/On9zevbm/ehfb/5Dhp4vz0mMqKQ4MGIrGkrToa7cZ+ryae/F5G/+To59",
+	"nq0PjHbndQGLz3aJxXNTsnDfVNjuEjgnmq0cMBbhpvmlq7fYXS509ce8LAjwaaPIHe6VFuU1xSo98qKV",
+	"HRg7Q1e7rF7PVSrobVPFranX94iMIV2u2+RZr2axXFNw0R5K40k59aEL7uX52BDo1xO7zSB/qaLedr3E",
+	"ciW5L3MJQ23VUi9URLGgnK9mZr4k4MNMQ9g6kHUobZl6wIk9pB12ZGBrHm2acmjgd3P81EnyPsZOm7XM",
+	"IWjaAkA+G5+rSsTJYqc2UOq4sX9B0j1b+nZxouYxL30PSQ3bKO4aecp08drIrSsC/JAvZqsUMn5k4VPN",
+	"5FLoNMdkV7a40RVwhU636g546r9uVy/6yrc+5g0/jt05MGRflQDR0up2M3iwvHek8jN+bWp9d8BAsyWe",
+	"F6B9tMbbCfjBIm8JLM8CkxW+L1kSOUzVWud57uyfhb6nq9FhG8OdNaE1gFtAt6AO1xrD+brzj3gbw+4K",
+	"3j8uOzzDWskWL2ENqxc12uJYYH2rdrir4G472bLaK9eLfxAG+G6Tgh9sUSu9NRX6UBt7AHjOE0GWg6D+",
+	"mINfS8sfaTpY/Tta6zSPNrX4G/itV7+6Zc0JJ+jecxZNel9UD1QXgG6xEBhAea33TcZ6lyc3I3vn7mwy",
+	"hQdpaoMdbFKDnVr3xnFj/1wbR9lO3JpmUB5cms2Aaf2YNdjM9Npa3wU59NAD+W4Mj/yma83rkg9R5rW7",
+	"KHi1/TmtYfqv9skvo6FaV3XtULj1oFjuUbFkLKnHWhJHrBlnZ/DUPmCMy5GRrBXGxjHQRsXuqwMflsUt",
+	"ez8WxIjQNQB29aKbQeyc8H0A8h5VuT7guCOO9zh0VG9farBVpUi/Cm8dMSKQJhE0G9IFHxqRvP14+/9b",
+	"1yDn9MwAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
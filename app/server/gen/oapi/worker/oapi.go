@@ -22,9 +22,104 @@ const (
 	TokenAuthScopes = "TokenAuth.Scopes"
 )
 
+// BundleFile defines model for BundleFile.
+type BundleFile struct {
+	// Digest Hex encoded SHA-256 digest of the content
+	Digest Digest `json:"digest"`
+
+	// Entry Name of the entry in the archive, empty if the file is left out
+	Entry *string `json:"entry,omitempty"`
+
+	// Path Path of the file relative to the data root of the instance. Instances reject absolute paths and paths
+	// escaping the data root.
+	Path FilePath `json:"path"`
+}
+
+// BundleManifest defines model for BundleManifest.
+type BundleManifest struct {
+	// ConfigDigest Hex encoded SHA-256 digest of the content
+	ConfigDigest Digest `json:"config_digest"`
+
+	// ConfigIncluded Whether `Caddyfile` is included in the archive
+	ConfigIncluded bool         `json:"config_included"`
+	Files          []BundleFile `json:"files"`
+}
+
+// BundleRequest defines model for BundleRequest.
+type BundleRequest struct {
+	// ConfigDigest Digest of the config the instance already has
+	ConfigDigest *string `json:"config_digest,omitempty"`
+
+	// Files Files the instance already has
+	Files *[]FileDigest `json:"files,omitempty"`
+}
+
+// ConfigDiagnostic defines model for ConfigDiagnostic.
+type ConfigDiagnostic struct {
+	Directive *string `json:"directive,omitempty"`
+
+	// Line Line in the rendered Caddyfile
+	Line    *int   `json:"line,omitempty"`
+	Message string `json:"message"`
+
+	// Section preconfig / site, by the section markers in the rendered Caddyfile
+	Section *string `json:"section,omitempty"`
+
+	// SectionLine Line relative to the start of the section
+	SectionLine *int `json:"section_line,omitempty"`
+
+	// Severity error / warning
+	Severity string `json:"severity"`
+
+	// SiteId Site owning the line, when section is site
+	SiteId *uint `json:"site_id,omitempty"`
+}
+
+// DriftEvent The running Caddy config differed from the config the instance applied
+type DriftEvent struct {
+	// DetectedAt unix second
+	DetectedAt Timestamp `json:"detected_at"`
+
+	// Differences JSON paths that differed, e.g. `apps.http.servers.srv0.routes`
+	Differences []string `json:"differences"`
+	Error       *string  `json:"error,omitempty"`
+
+	// Reapplied Whether the applied config was loaded again
+	Reapplied bool `json:"reapplied"`
+}
+
+// FileDigest defines model for FileDigest.
+type FileDigest struct {
+	// Digest Hex encoded SHA-256 digest of the content
+	Digest Digest `json:"digest"`
+
+	// Path Path of the file relative to the data root of the instance. Instances reject absolute paths and paths
+	// escaping the data root.
+	Path FilePath `json:"path"`
+}
+
+// FileSyncResult defines model for FileSyncResult.
+type FileSyncResult struct {
+	// Digest Digest of the local file after this round
+	Digest *string `json:"digest,omitempty"`
+	Error  *string `json:"error,omitempty"`
+
+	// Path Path of the file relative to the data root of the instance. Instances reject absolute paths and paths
+	// escaping the data root.
+	Path FilePath `json:"path"`
+
+	// Status unchanged / updated / failed / rolled_back
+	Status string `json:"status"`
+}
+
 // FileUpdateRecord defines model for FileUpdateRecord.
 type FileUpdateRecord struct {
-	Path string `json:"path"`
+	// Digest Hex encoded SHA-256 digest of the content
+	Digest Digest `json:"digest"`
+
+	// Path Path of the file relative to the data root of the instance. Instances reject absolute paths and paths
+	// escaping the data root.
+	Path FilePath `json:"path"`
 
 	// UpdatedAt unix second
 	UpdatedAt Timestamp `json:"updated_at"`
@@ -32,34 +127,96 @@ type FileUpdateRecord struct {
 
 // HeartbeatRes defines model for HeartbeatRes.
 type HeartbeatRes struct {
+	// ConfigDigest Hex encoded SHA-256 digest of the content
+	ConfigDigest Digest `json:"config_digest"`
+
 	// ConfigUpdatedAt unix second
 	ConfigUpdatedAt Timestamp          `json:"config_updated_at"`
 	FilesUpdatedAt  []FileUpdateRecord `json:"files_updated_at"`
 }
 
+// SyncReport defines model for SyncReport.
+type SyncReport struct {
+	// CaddyLoadResponse Response body of the last Caddy /load request in this round
+	CaddyLoadResponse *string `json:"caddy_load_response,omitempty"`
+	CaddyVersion      *string `json:"caddy_version,omitempty"`
+
+	// ConfigDigest Digest of the config currently loaded into Caddy, empty if none
+	ConfigDigest string `json:"config_digest"`
+
+	// Diagnostics Warnings and errors from adapting the new config with Caddy /adapt
+	Diagnostics *[]ConfigDiagnostic `json:"diagnostics,omitempty"`
+
+	// Drift The running Caddy config differed from the config the instance applied
+	Drift *DriftEvent       `json:"drift,omitempty"`
+	Error *string           `json:"error,omitempty"`
+	Files *[]FileSyncResult `json:"files,omitempty"`
+
+	// Status ok / failed
+	Status string `json:"status"`
+
+	// SyncedAt unix second
+	SyncedAt      *Timestamp `json:"synced_at,omitempty"`
+	WorkerVersion *string    `json:"worker_version,omitempty"`
+}
+
+// Digest Hex encoded SHA-256 digest of the content
+type Digest = string
+
+// FilePath Path of the file relative to the data root of the instance. Instances reject absolute paths and paths
+// escaping the data root.
+type FilePath = string
+
 // Timestamp unix second
 type Timestamp = int64
 
 // Id defines model for id.
 type Id = uint
 
+// IfNoneMatch defines model for ifNoneMatch.
+type IfNoneMatch = string
+
+// GetConfigParams defines parameters for GetConfig.
+type GetConfigParams struct {
+	// IfNoneMatch Digests the instance already has, as quoted ETags
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
 // GetFilesParams defines parameters for GetFiles.
 type GetFilesParams struct {
-	// XFilePath Path of target file
+	// XFilePath Path of target file, relative to the data root of the instance
 	XFilePath *string `json:"X-File-Path,omitempty"`
+
+	// IfNoneMatch Digests the instance already has, as quoted ETags
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
 }
 
+// GetBundleJSONRequestBody defines body for GetBundle for application/json ContentType.
+type GetBundleJSONRequestBody = BundleRequest
+
+// ReportJSONRequestBody defines body for Report for application/json ContentType.
+type ReportJSONRequestBody = SyncReport
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// get config and files in a single archive
+	// (POST /{id}/bundle)
+	GetBundle(ctx echo.Context, id Id) error
 	// get config
 	// (GET /{id}/config)
-	GetConfig(ctx echo.Context, id Id) error
+	GetConfig(ctx echo.Context, id Id, params GetConfigParams) error
+	// subscribe to update notifications
+	// (GET /{id}/events)
+	Events(ctx echo.Context, id Id) error
 	// get single file
 	// (GET /{id}/file)
 	GetFiles(ctx echo.Context, id Id, params GetFilesParams) error
 	// heartbeat event
 	// (GET /{id}/heartbeat)
 	Heartbeat(ctx echo.Context, id Id) error
+	// report sync result
+	// (POST /{id}/report)
+	Report(ctx echo.Context, id Id) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -67,6 +224,24 @@ type ServerInterfaceWrapper struct {
 	Handler ServerInterface
 }
 
+// GetBundle converts echo context to params.
+func (w *ServerInterfaceWrapper) GetBundle(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id Id
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: false})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(TokenAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetBundle(ctx, id)
+	return err
+}
+
 // GetConfig converts echo context to params.
 func (w *ServerInterfaceWrapper) GetConfig(ctx echo.Context) error {
 	var err error
@@ -80,8 +255,46 @@ func (w *ServerInterfaceWrapper) GetConfig(ctx echo.Context) error {
 
 	ctx.Set(TokenAuthScopes, []string{})
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetConfigParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch IfNoneMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-None-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-None-Match: %s", err))
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetConfig(ctx, id)
+	err = w.Handler.GetConfig(ctx, id, params)
+	return err
+}
+
+// Events converts echo context to params.
+func (w *ServerInterfaceWrapper) Events(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id Id
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: false})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(TokenAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.Events(ctx, id)
 	return err
 }
 
@@ -117,6 +330,21 @@ func (w *ServerInterfaceWrapper) GetFiles(ctx echo.Context) error {
 
 		params.XFilePath = &XFilePath
 	}
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch IfNoneMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-None-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-None-Match: %s", err))
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+	}
 
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.GetFiles(ctx, id, params)
@@ -141,6 +369,24 @@ func (w *ServerInterfaceWrapper) Heartbeat(ctx echo.Context) error {
 	return err
 }
 
+// Report converts echo context to params.
+func (w *ServerInterfaceWrapper) Report(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id Id
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: false})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(TokenAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.Report(ctx, id)
+	return err
+}
+
 // This is a simple interface which specifies echo.Route addition functions which
 // are present on both echo.Echo and echo.Group, since we want to allow using
 // either of them for path registration
@@ -169,28 +415,59 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.POST(baseURL+"/:id/bundle", wrapper.GetBundle)
 	router.GET(baseURL+"/:id/config", wrapper.GetConfig)
+	router.GET(baseURL+"/:id/events", wrapper.Events)
 	router.GET(baseURL+"/:id/file", wrapper.GetFiles)
 	router.GET(baseURL+"/:id/heartbeat", wrapper.Heartbeat)
+	router.POST(baseURL+"/:id/report", wrapper.Report)
 
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/9RWX2/bNhD/KsRtDxsgW96W7UFvQYtteSmCdsMKBEZBU2eLjURyx5Nbw9B3H46S5Tiy",
-	"kSBYHvqSWBZ59/vHo/dgfBO8Q8cRij0ETbpBRkpPtpS/JUZDNrD1Dgq4eQsZWPkUNFeQgdMNQiFrM4im",
-	"wkbLprWnRjMU0FrHkAHvQlrlGDdI0HXdYXXq9Lut8e9Qasb3aDylvoF8QGKLAzKu5P9QKDJZt4EugzZt",
-	"Kz9Jtz18T7iGAr7Lj7zyoU/OtsHIugkg3Qn/bS1hCcXdgcqDUssRsl99RsPS6U/UxCvU/H6AdALQeLe2",
-	"m08vgpPB2tYYH222jE18qspEuW5Eron0bsJ1ivNM93P0j3gnoWid/aoiGu8kBaP31vFvV+fMzyCiacny",
-	"7oPQ6AX8y9+ju257mxM92bRCTUjHIhVz6ONj3dqnRFiu5c0bXZY79RZru0XaqXfIXzzdq5n6x9M9krq+",
-	"vYEMtkixB72YL+YLIeYDOh0sFPDLfDH/CbKUh4Qp39uyy3vJ5HmDyRnxXQv3mxIK+AP5Tb8iOzlAd+ed",
-	"Oy7JbQndUuyJwbvYy/DzYjHEidGlboxfOTfCTnwa1dFnjoPocmrNh9YYjFF4Xi2upta98yq2plLWRdbO",
-	"oPqhxBoZS+VJsViiGhsbzab6UYr82sN7NBQcIzldq4i0RVJI5E99Tmo8cPhuKcRj2zSadlCIsMocRGS9",
-	"EfngSzIOllKod+IgwCUf5DTEF9mQPeZ0q7lSfq1Yk4BLnYfJV6EuUyaH2fdxJn1nt/0UuWzO01brEGpr",
-	"EqXcG0aeRSbUzanp4wFbWSfyZc+PQTaAT/0/zvrJUc6ueWpq/06Nx34qxhHRc8du93/EUL5KCF43jtG6",
-	"TY0HrpczWR2uhYvBHC+OVxoQD1PzOYoE+2d6c3KlfbvTY7RA4Rb73xsTu1I5qd8L31INBeQ62HxY0y27",
-	"/wIAAP//VjrxcQ8JAAA=",
+	"H4sIAAAAAAAC/9RZbXPbNhL+KztsPyQzNGU7chrrPrlx75Kba5qzc9ObiT0WRCwl1CTAAKAdNaP/frMA",
+	"SJEWJL+k6Vw/mbbBfX92Hyy/JLmqaiVRWpNMviQLZBy1e/zpA5vTT44m16K2Qslkkvy7URY5nL852Ts8",
+	"eglczNFYUAXYBUKupEVpUzCsQmDG/TEcERIWyLSdIbNJmph8gRUj+XZZYzJJjNVCzpPVapUmNdOsQhsM",
+	"EXzTjLenSZoIeqqZXSRpIllFUgQfyC6UrphNJkkjJGkNuoS0OEedkC5RvFMSf2Y2X2yqOXW2ez+ENJbJ",
+	"HIGVGhlfwoKZlJz85ENC8TKtVT6Oa7veFnukZs/rucd9/0/n+4+N5CX+XZRIv9Va1aitQPc/H1h6+l5j",
+	"kUyS70brZI6CkFE4tUoTlFYvN318R7kKCXRHKFX0C9P5QtxgCljVdgnCHylEiSAMlFhYUE0vqq0Lqc/J",
+	"PXaRnPd0jjzW+KkRGnky+dgmNNh92YlXs98wd474oPzMpChCAIaByZUsxPzqsfEJrwmZlw3HSNH9ukC7",
+	"QA3T14zzJTkwpUi0L9yJ2zowM6VKZJJ00Eu+qC1W5j7betlfddKY1my5EbWhz5vOtKq3x/MMPzUPC2cM",
+	"I70WUIj5VsDEqqWLyVAuuW12CXpQCEnKaZfijRBuxOK1s/9UsLlUxoo8BjuNuaUEb6I3TUohcdOXfwmJ",
+	"bXlolBw1cuiqKNKY0qRCY9g8rsRg7gXf1VNrDAkYgREWU5gtndLwBlRMX6M2O23Bz6yqS6dRWIxlLEi7",
+	"2uGsxpJRkMAqb4BluiuS1v6Y3wZvUAsbaVSotdIwglumJRnSt9T9L2qqsHgVmyDnwiKoW5LkbCJfUrhd",
+	"oOyCJQyECDxgjvTB2PmwTmMMd6daFPanG5QRVH2g7DTSmeeS0yKLi6JwKSu0qrZDrq5L4WB/p3jRYm6R",
+	"X7F7G6MVFRrLqppMDVplHkPqP89/eQfUtgmuzHYmpoDZPIMpq2uTLaytM4P6BrXJjL7Zz7RqLJppH8kb",
+	"6RsCNg15jp3U2Pq8tW+75uwPtUG7ZQZKxah7szkTMtK07+S2H8FhXPo2xNLda0VfPcu/8YQlU8+XMj9D",
+	"05Q7zd01C0qVs9ITBlZYlwBhQKtG8hhUt+f2cd6mibHMNpFCbWS+YHKOHEbQ1JxZ91QwUboHrcoS+dWM",
+	"5deD5hKObtocj2vQvi2u/3HSzjBXmv/ZhZC2vjwO/3E/e6J2FtOblvafodl0+OvI2pPcCZTjzssPphOD",
+	"BD6Qlw1idZerbVgTi6KHY610jKHRgLiiPnal0dRKmshYPgv/gZniyw6izNgwX0b0PmjPAj1B2IVWr5O6",
+	"eWAia7zcHGavsjEsDiZZlkXffQKjzButUdpy2fZrIa3ypvduKFLJKGPhHaGLtIVfPaEwwCQH14WMn66M",
+	"s9q2BEHibTc1hF20UXNnHspGN9hlZMRxYgX3CepRh52d83H3jTttP2Lcts6qrrtGOmid6jpKypYyfzxo",
+	"bxVx137J7W7Hwda75RYD17ZKfIOfAWWu+H0rj4HT+GK2n4/Hh8evivwgPxgfs2JWjPNXx8cvi9nx4fjw",
+	"B4bjAxy/HB/Pjl+MczY+Pjo+Ppj98OrocPbq6GjbJel96PVDG+mvrTV0aoN6c2YZaKU6m1uWmMHb8GRA",
+	"I8UC2MyosrEY6BwBwj1dSDQ5q1ssdCKzCzlwPUdtzehgRD+zGquYK+ukRga0+AwGcyV5n3ULaV+O47Tb",
+	"YN4Q1T6nivGl/kFdozxpfKxcJTk+h0xj74pAfNSvW4QsFB21wjofPLBPsRQ3qJfwDi1VHuzBr64A4eT9",
+	"2yRNujJM9rP9bJ8cUzVKVotkkrzI9rODxE9nZ9Poi+Cr0cxdtOn3WsXK7Qxto6UBBpbpbP57u0pwVcaE",
+	"uwxMq7D3yH4zSk7h2XAb8jwdXuxCx2Kmv7W4kJTYtmAMNHQYpu6X0TQDuny0aoCVt2xpoBTGGkAXEldm",
+	"d4rpb0EWk53S24UyeCF5WKJVtPty7yiJvSuoHzhuKjGN3WKpVdDG4BnH0jKoFMfnGZzI0PTde9oH7kI6",
+	"++xCyLmvTJqSjKL7lieT5B9ofbSS4Y7xY7wLrY+MBE9Wl769oLE/Kr4M3MWG25sj/rlTNaLEdLXHHrbk",
+	"abcvq5VvY35Wu9o53N/foWz+u6iHyjrYzIRkehmhrav0TuGdN3mOxlARj722O+tWecNK0bEDf24c2SUq",
+	"ME2+WN9DKWlokYPSYAmXUAnjCuE5CTmKK7OoJSvB3xb9RB6A3WWsB/OPl5Qc01QV+TtJ5mi7wpc8FKaQ",
+	"wMAIOS8HKzo2pwIIwyW5JDUerF4AmTfHHViNr1Ey8OsrKmiN4YrIHQy/fP/69N3V6cmHk6uzX375sBpd",
+	"NPv7L3JqFe4Jp+nwLq+xLlnud2EX0j0vVEmIdTxEWNNr8TMsFIFIMd42a+9IBj+xfNHtNtw+xngJLKyF",
+	"IFdVhdJeyGfT7yDnctLtk6aUwPBHIyyCN1rwYPJzMAp6JAtyJmFGXaSukQNdqdpxFHYuJGULRj1LehJG",
+	"0/tP9fb9q8t7oWbxsx3lbVqHQHswrNLYd5WYneHYyJ1xAl/EYWapD4pCIIdngZA4VKGBwaeG538dqO4E",
+	"I960X6iiYDx36vfOUVpwrNiAsRpZ5QbF1F+tpuCkuLUe/aRdH82L/i1D6dAt3PARZh0evzlIL+QAm2ah",
+	"mtKNUgkaideCXWjVzBfrr10ZvPa4cjtG3xOcATVqobjIWVkuqTVcI9aMWEd2IdfMLKigf0KtypLA08mm",
+	"1xgUrCwdxMilsG4l78lVqVyApVtaxQDn4/X0iXg/fFzY97xJT0TQH1PFj6hL08xIz8yxaF8/FEpRhLlr",
+	"dpZr2ytCsW50ODccntrgttB/pglLpDl9+BVg2/fK/+6RiXvvw0Zra8L+8H7bpzYqtxivm6+gOH/1Xty2",
+	"qG/ckwNRaj9Pba309Tf9beX+pvfV/1u0mKcz78Fi8ps3n2+WrvUwcI12Z7r0eoMYvYG6CeqX9ejoIk00",
+	"twP0n3ba/blRFbprlitHt/dJHddW7utT2PYjeAsC28zALzDDZyIlyyXkTOslMHBbrzCgaUBeY23BIFWD",
+	"RRqPkgNXbpoFPrzeX3oj3c4q2xhvXuX/04Wvt8mN3vYilfbw+5kX+2dPS6+3n4hoFTqJ7gugE9joMpkk",
+	"I1aLUTizulz9bwBe474lkiQAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
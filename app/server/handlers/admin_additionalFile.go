@@ -73,6 +73,11 @@ func (a *App) additionalFileUpdateClearCache(ctx context.Context, id uint, oldFi
 		}
 	}
 
+	// 通知实例立即同步
+	for _, instance := range instances {
+		a.instanceNotify(ctx, instance.ID)
+	}
+
 	return nil
 }
 
@@ -255,15 +260,8 @@ func (a *App) AdditionalFileInfoUpdate(c echo.Context, id uint) error {
 		}
 	}
 
-	// 如果文件名称发生变更，需要清理旧缓存
-	if req.Filename != nil && *req.Filename != aFile.Filename {
-		if err := a.additionalFileUpdateClearCache(rctx, aFile.ID, aFile.Filename, *req.Filename); err != nil {
-			a.l.Error("failed to clear cache", zap.Error(err))
-			return a.er(c, http.StatusInternalServerError)
-		}
-	}
-
 	// 更新
+	oldFilename := aFile.Filename
 	a.additionalFileMapFields(&req, &aFile)
 
 	// 更新信息
@@ -272,6 +270,14 @@ func (a *App) AdditionalFileInfoUpdate(c echo.Context, id uint) error {
 		return a.er(c, http.StatusInternalServerError)
 	}
 
+	// 如果文件名称发生变更，需要清理旧缓存（写入之后再清理，避免实例在写入之前重新拉取到旧数据）
+	if aFile.Filename != oldFilename {
+		if err := a.additionalFileUpdateClearCache(rctx, aFile.ID, oldFilename, aFile.Filename); err != nil {
+			a.l.Error("failed to clear cache", zap.Error(err))
+			return a.er(c, http.StatusInternalServerError)
+		}
+	}
+
 	return c.JSON(http.StatusOK, &admin.AdditionalFileInfoWithID{
 		Id:       &aFile.ID,
 		Name:     &aFile.Name,
@@ -0,0 +1,338 @@
+package handlers
+
+import (
+	"caddy-delivery-network/app/server/certutil"
+	"caddy-delivery-network/app/server/constants"
+	"caddy-delivery-network/app/server/gen/oapi/admin"
+	"caddy-delivery-network/app/server/models"
+	"caddy-delivery-network/app/server/utils"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"net/http"
+	"time"
+)
+
+func (a *App) caMapResponse(ca *models.CertAuthority) *admin.CAInfoWithID {
+	return &admin.CAInfoWithID{
+		Id:            &ca.ID,
+		Name:          &ca.Name,
+		ParentId:      ca.ParentID,
+		Certificate:   &ca.Certificate,
+		HasPrivateKey: utils.P(ca.PrivateKey != nil),
+		ExpiresAt:     utils.P(ca.ExpiresAt.Unix()),
+	}
+}
+
+func (a *App) CaCreate(c echo.Context) error {
+	// 抓取 user 信息（认证）
+	err, statusCode := a.authAdmin(c, true, nil)
+	if err != nil {
+		a.l.Error("failed to auth", zap.Error(err))
+		return a.er(c, statusCode)
+	}
+
+	rctx := c.Request().Context()
+
+	// 绑定请求体
+	var req admin.CaCreateJSONRequestBody
+	if err = c.Bind(&req); err != nil {
+		a.l.Error("failed to bind request", zap.Error(err))
+		return a.er(c, http.StatusBadRequest)
+	}
+	if req.CommonName == "" {
+		return a.er(c, http.StatusBadRequest)
+	}
+
+	// 没有上级时创建根证书
+	var parent *caSigner
+	validity := constants.CAValidityRoot
+	if req.ParentId != nil {
+		if parent, err = a.caGetSigner(rctx, *req.ParentId); err != nil {
+			a.l.Error("failed to get parent ca", zap.Uint("parent", *req.ParentId), zap.Error(err))
+			return a.er(c, http.StatusBadRequest)
+		}
+		validity = constants.CAValidityIntermediate
+	}
+	if req.Validity != nil && *req.Validity != "" {
+		if validity, err = time.ParseDuration(*req.Validity); err != nil || validity <= 0 {
+			return a.er(c, http.StatusBadRequest)
+		}
+	}
+
+	var keyType certutil.KeyType
+	if req.KeyType != nil {
+		keyType = certutil.KeyType(*req.KeyType)
+	}
+	key, err := certutil.GenerateKey(keyType)
+	if err != nil {
+		a.l.Error("failed to generate ca key", zap.Error(err))
+		return a.er(c, http.StatusBadRequest)
+	}
+
+	var der []byte
+	if parent != nil {
+		der, err = certutil.CreateCA(req.CommonName, key, validity, parent.cert, parent.key)
+	} else {
+		der, err = certutil.CreateCA(req.CommonName, key, validity, nil, nil)
+	}
+	if err != nil {
+		a.l.Error("failed to create ca", zap.Error(err))
+		return a.er(c, http.StatusBadRequest)
+	}
+	keyPEM, err := certutil.EncodePrivateKeyPEM(key)
+	if err != nil {
+		a.l.Error("failed to encode ca key", zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+
+	// 创建
+	ca := models.CertAuthority{
+		Name:        req.CommonName,
+		ParentID:    req.ParentId,
+		Certificate: certutil.EncodeCertificatesPEM(der),
+	}
+	if req.Name != nil && *req.Name != "" {
+		ca.Name = *req.Name
+	}
+	if certs, err := certutil.ParseCertificatesPEM([]byte(ca.Certificate)); err == nil {
+		ca.ExpiresAt = certs[0].NotAfter
+	}
+	if ca.PrivateKey, err = a.aesEncrypt(keyPEM); err != nil {
+		a.l.Error("failed to encrypt ca key", zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+
+	if err := a.db.WithContext(rctx).Create(&ca).Error; err != nil {
+		a.l.Error("failed to create ca", zap.Any("ca", ca), zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusCreated, a.caMapResponse(&ca))
+}
+
+func (a *App) CaImport(c echo.Context) error {
+	// 抓取 user 信息（认证）
+	err, statusCode := a.authAdmin(c, true, nil)
+	if err != nil {
+		a.l.Error("failed to auth", zap.Error(err))
+		return a.er(c, statusCode)
+	}
+
+	rctx := c.Request().Context()
+
+	// 绑定请求体
+	var req admin.CaImportJSONRequestBody
+	if err = c.Bind(&req); err != nil {
+		a.l.Error("failed to bind request", zap.Error(err))
+		return a.er(c, http.StatusBadRequest)
+	}
+
+	// 检查证书内容（私钥是可选的）
+	var privateKey string
+	if req.PrivateKey != nil {
+		privateKey = *req.PrivateKey
+	}
+	var validationErrs certutil.ValidationErrors
+	for _, e := range certutil.Validate(req.Certificate, privateKey, "", time.Now()) {
+		if e.Code != certutil.ValidationMissingPrivateKey {
+			validationErrs = append(validationErrs, e)
+		}
+	}
+	if len(validationErrs) > 0 {
+		return a.erCertValidation(c, validationErrs)
+	}
+
+	certs, err := certutil.ParseCertificatesPEM([]byte(req.Certificate))
+	if err != nil {
+		return a.er(c, http.StatusBadRequest)
+	}
+	if !certs[0].IsCA {
+		return a.erCertValidation(c, certutil.ValidationErrors{
+			{Field: "certificate", Code: certutil.ValidationNotCA, Message: "certificate is not a ca"},
+		})
+	}
+
+	// 检查与上级的签发关系
+	if req.ParentId != nil {
+		var parent models.CertAuthority
+		if err := a.db.WithContext(rctx).First(&parent, "id = ?", *req.ParentId).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return a.er(c, http.StatusBadRequest)
+			}
+			a.l.Error("failed to get parent ca", zap.Uint("parent", *req.ParentId), zap.Error(err))
+			return a.er(c, http.StatusInternalServerError)
+		}
+		parentCerts, err := certutil.ParseCertificatesPEM([]byte(parent.Certificate))
+		if err != nil {
+			a.l.Error("failed to parse parent ca", zap.Uint("parent", parent.ID), zap.Error(err))
+			return a.er(c, http.StatusInternalServerError)
+		}
+		if err := certutil.CheckChain(certs[0], parentCerts); err != nil {
+			return a.erCertValidation(c, certutil.ValidationErrors{
+				{Field: "parent_id", Code: certutil.ValidationChainBroken, Message: err.Error()},
+			})
+		}
+	}
+
+	// 创建
+	ca := models.CertAuthority{
+		Name:        certs[0].Subject.CommonName,
+		ParentID:    req.ParentId,
+		ExpiresAt:   certs[0].NotAfter,
+		Certificate: certutil.EncodeCertificatesPEM(certs[0].Raw),
+	}
+	if req.Name != nil && *req.Name != "" {
+		ca.Name = *req.Name
+	}
+	if privateKey != "" {
+		if ca.PrivateKey, err = a.aesEncrypt([]byte(privateKey)); err != nil {
+			a.l.Error("failed to encrypt ca key", zap.Error(err))
+			return a.er(c, http.StatusInternalServerError)
+		}
+	}
+
+	if err := a.db.WithContext(rctx).Create(&ca).Error; err != nil {
+		a.l.Error("failed to create ca", zap.Any("ca", ca), zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusCreated, a.caMapResponse(&ca))
+}
+
+func (a *App) CaList(c echo.Context, params admin.CaListParams) error {
+	// 抓取 user 信息（认证）
+	err, statusCode := a.authAdmin(c, false, nil)
+	if err != nil {
+		a.l.Error("failed to auth", zap.Error(err))
+		return a.er(c, statusCode)
+	}
+
+	rctx := c.Request().Context()
+
+	var (
+		cas      []models.CertAuthority
+		casCount int64
+	)
+
+	showAll, page, limit := a.parsePagination(params.Page, params.Limit)
+	queryBase := a.db.WithContext(rctx).Model(&models.CertAuthority{}).Order("id ASC")
+	if !showAll {
+		queryBase = queryBase.Limit(limit).Offset(page * limit)
+	}
+
+	if err := queryBase.Find(&cas).Error; err != nil {
+		a.l.Error("failed to get ca list", zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+	if err := a.db.WithContext(rctx).Model(&models.CertAuthority{}).Count(&casCount).Error; err != nil {
+		a.l.Error("failed to count ca", zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+
+	resCAs := []admin.CAInfoWithID{}
+	for _, ca := range cas {
+		resCAs = append(resCAs, *a.caMapResponse(&ca))
+	}
+
+	return c.JSON(http.StatusOK, &admin.CAListResponse{
+		Limit:   &limit,
+		PageMax: utils.P(a.calcMaxPage(casCount, showAll, limit)),
+		List:    &resCAs,
+	})
+}
+
+func (a *App) CaInfoGet(c echo.Context, id uint) error {
+	// 抓取 user 信息（认证）
+	err, statusCode := a.authAdmin(c, false, nil)
+	if err != nil {
+		a.l.Error("failed to auth", zap.Error(err))
+		return a.er(c, statusCode)
+	}
+
+	rctx := c.Request().Context()
+
+	// 从数据库中获得
+	var ca models.CertAuthority
+	if err := a.db.WithContext(rctx).First(&ca, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return a.er(c, http.StatusNotFound)
+		} else {
+			a.l.Error("failed to get ca", zap.Uint("id", id), zap.Error(err))
+			return a.er(c, http.StatusInternalServerError)
+		}
+	}
+
+	return c.JSON(http.StatusOK, a.caMapResponse(&ca))
+}
+
+func (a *App) CaInfoUpdate(c echo.Context, id uint) error {
+	// 抓取 user 信息（认证）
+	err, statusCode := a.authAdmin(c, true, nil)
+	if err != nil {
+		a.l.Error("failed to get user", zap.Error(err))
+		return a.er(c, statusCode)
+	}
+
+	rctx := c.Request().Context()
+
+	// 绑定请求体
+	var req admin.CaInfoUpdateJSONRequestBody
+	if err = c.Bind(&req); err != nil {
+		a.l.Error("failed to bind request", zap.Error(err))
+		return a.er(c, http.StatusBadRequest)
+	}
+
+	// 从数据库中获得
+	var ca models.CertAuthority
+	if err := a.db.WithContext(rctx).First(&ca, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return a.er(c, http.StatusNotFound)
+		} else {
+			a.l.Error("failed to get ca", zap.Uint("id", id), zap.Error(err))
+			return a.er(c, http.StatusInternalServerError)
+		}
+	}
+
+	// 证书内容不能修改，只能更新名称（也就不需要清理实例的缓存）
+	if req.Name != nil {
+		ca.Name = *req.Name
+		if err := a.db.WithContext(rctx).Model(&ca).UpdateColumn("name", ca.Name).Error; err != nil {
+			a.l.Error("failed to update ca", zap.Any("ca", ca), zap.Error(err))
+			return a.er(c, http.StatusInternalServerError)
+		}
+	}
+
+	return c.JSON(http.StatusOK, a.caMapResponse(&ca))
+}
+
+func (a *App) CaDelete(c echo.Context, id uint) error {
+	// 抓取 user 信息（认证）
+	err, statusCode := a.authAdmin(c, true, nil)
+	if err != nil {
+		a.l.Error("failed to get user", zap.Error(err))
+		return a.er(c, statusCode)
+	}
+
+	rctx := c.Request().Context()
+
+	// 检查是否可以被删除
+	if ableToDelete, err := a.caCheckAbleToDelete(rctx, id); err != nil {
+		a.l.Error("failed to check able-to-delete", zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	} else if !ableToDelete {
+		return a.er(c, http.StatusPreconditionFailed)
+	}
+
+	// 删除
+	result := a.db.WithContext(rctx).Delete(&models.CertAuthority{}, id)
+	if result.Error != nil {
+		a.l.Error("failed to delete ca", zap.Uint("id", id), zap.Error(result.Error))
+		return a.er(c, http.StatusInternalServerError)
+	} else if result.RowsAffected == 0 {
+		return a.er(c, http.StatusNotFound)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"caddy-delivery-network/app/server/certutil"
 	"caddy-delivery-network/app/server/constants"
 	"caddy-delivery-network/app/server/gen/oapi/admin"
 	"caddy-delivery-network/app/server/models"
@@ -53,6 +54,36 @@ func (a *App) certMapFields(req *admin.CertInfoInput, cert *models.Cert) {
 	}
 }
 
+// certValidate 合并请求与已有的证书内容后检查一致性，请求没有涉及证书内容时跳过
+func (a *App) certValidate(req *admin.CertInfoInput, cert *models.Cert) (certutil.ValidationErrors, error) {
+	if req.Certificate == nil && req.PrivateKey == nil && req.IntermediateCertificate == nil {
+		return nil, nil
+	}
+
+	certificate := cert.Certificate
+	if req.Certificate != nil {
+		certificate = *req.Certificate
+	}
+	intermediate := cert.IntermediateCertificate
+	if req.IntermediateCertificate != nil {
+		intermediate = *req.IntermediateCertificate
+	}
+
+	// 只更新证书时需要与已有的私钥比对
+	var privateKey string
+	if req.PrivateKey != nil {
+		privateKey = *req.PrivateKey
+	} else if cert.PrivateKey != nil {
+		keyPEM, err := a.aesDecrypt(cert.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt private key: %w", err)
+		}
+		privateKey = string(keyPEM)
+	}
+
+	return certutil.Validate(certificate, privateKey, intermediate, time.Now()), nil
+}
+
 func (a *App) certParseMeta(certificate string) (time.Time, []string) {
 	block, _ := pem.Decode([]byte(certificate))
 	if block == nil {
@@ -91,6 +122,9 @@ func (a *App) certUpdateClearCache(ctx context.Context, id uint, isCACertStatusC
 			}
 		}
 		for _, instance := range instances {
+			// 清理配置数据缓存（站点配置会根据证书的签发状态与 provider 变化）
+			a.rdb.Del(ctx, fmt.Sprintf(constants.CacheKeyInstanceConfig, instance.ID))
+
 			// 清理心跳数据缓存（这里包含了文件对应的更新时间）
 			heartbeatCacheKey := fmt.Sprintf(constants.CacheKeyInstanceHeartbeat, instance.ID)
 			a.rdb.Del(ctx, heartbeatCacheKey)
@@ -100,6 +134,9 @@ func (a *App) certUpdateClearCache(ctx context.Context, id uint, isCACertStatusC
 				filesCacheKey := fmt.Sprintf(constants.CacheKeyInstanceFiles, instance.ID)
 				a.rdb.Del(ctx, filesCacheKey)
 			}
+
+			// 通知实例立即同步
+			a.instanceNotify(ctx, instance.ID)
 		}
 	}
 
@@ -140,11 +177,33 @@ func (a *App) CertCreate(c echo.Context) error {
 		return a.er(c, http.StatusBadRequest)
 	}
 
-	// 创建
+	// 检查提供方配置
+	if req.Provider != nil {
+		if err, statusCode := a.certValidateProvider(rctx, json.RawMessage(*req.Provider)); err != nil {
+			a.l.Error("failed to validate cert provider", zap.Error(err))
+			return a.er(c, statusCode)
+		}
+	}
+
+	// 检查证书内容
 	var cert models.Cert
+	if validationErrs, err := a.certValidate(&req, &cert); err != nil {
+		a.l.Error("failed to validate cert", zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	} else if len(validationErrs) > 0 {
+		return a.erCertValidation(c, validationErrs)
+	}
+
+	// 创建
 	a.certMapFields(&req, &cert)
 
-	if err := a.db.WithContext(rctx).Create(&cert).Error; err != nil {
+	// 创建并记录版本
+	if err := a.db.WithContext(rctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&cert).Error; err != nil {
+			return fmt.Errorf("failed to create cert: %w", err)
+		}
+		return a.certSaveVersion(tx, &cert, constants.CertVersionSourceUpload)
+	}); err != nil {
 		a.l.Error("failed to create cert", zap.Any("cert", cert), zap.Error(err))
 		return a.er(c, http.StatusInternalServerError)
 	}
@@ -233,6 +292,7 @@ func (a *App) CertInfoGet(c echo.Context, id uint) error {
 		Domains:      (*[]string)(&cert.Domains),
 		ExpiresAt:    utils.P(cert.ExpiresAt.Unix()),
 		IsManualMode: utils.P(cert.Provider == nil),
+		Csr:          &cert.CSR, // 签发请求是公开信息，方便重新提交给 CA
 	})
 }
 
@@ -253,6 +313,23 @@ func (a *App) CertInfoUpdate(c echo.Context, id uint) error {
 		return a.er(c, http.StatusBadRequest)
 	}
 
+	// 修改证书内容时加锁，避免与续期、回滚同时进行
+	if req.Certificate != nil || req.PrivateKey != nil || req.IntermediateCertificate != nil {
+		lockKey := fmt.Sprintf(constants.LockKeyCertRenew, id)
+		lockToken, ok, err := a.lockAcquire(rctx, lockKey, constants.CertRenewLockTTL)
+		if err != nil {
+			a.l.Error("failed to acquire cert renew lock", zap.Uint("id", id), zap.Error(err))
+			return a.er(c, http.StatusInternalServerError)
+		} else if !ok {
+			return a.er(c, http.StatusConflict)
+		}
+		defer func() {
+			if err := a.lockRelease(context.WithoutCancel(rctx), lockKey, lockToken); err != nil {
+				a.l.Error("failed to release cert renew lock", zap.Uint("id", id), zap.Error(err))
+			}
+		}()
+	}
+
 	// 从数据库中获得
 	var cert models.Cert
 	if err := a.db.WithContext(rctx).First(&cert, "id = ?", id).Error; err != nil {
@@ -264,24 +341,70 @@ func (a *App) CertInfoUpdate(c echo.Context, id uint) error {
 		}
 	}
 
-	// 如果证书部分发生变更，需要清理旧缓存（已知私钥会随着证书变化，所以没必要单独验证）
-	if req.Certificate != nil && *req.Certificate != cert.Certificate ||
-		req.IntermediateCertificate != nil && *req.IntermediateCertificate != cert.IntermediateCertificate {
-		if err := a.certUpdateClearCache(rctx, cert.ID,
-			(req.IntermediateCertificate == nil || *req.IntermediateCertificate == "") != // 新 CA 证书为空
-				(cert.IntermediateCertificate == ""), // 旧 CA 证书为空
-		); err != nil {
-			a.l.Error("failed to clear cache", zap.Error(err))
+	// 检查提供方配置（切换成手动模式时会被清理，不需要检查）
+	if req.Provider != nil && (req.IsManualMode == nil || !*req.IsManualMode) {
+		if err, statusCode := a.certValidateProvider(rctx, json.RawMessage(*req.Provider)); err != nil {
+			a.l.Error("failed to validate cert provider", zap.Uint("id", id), zap.Error(err))
+			return a.er(c, statusCode)
+		}
+	}
+
+	// 检查证书内容
+	if validationErrs, err := a.certValidate(&req, &cert); err != nil {
+		a.l.Error("failed to validate cert", zap.Uint("id", id), zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	} else if len(validationErrs) > 0 {
+		return a.erCertValidation(c, validationErrs)
+	}
+
+	// 域名变化时检查使用这张证书的站点是否仍然被覆盖
+	var coverageIssues []siteCoverageIssue
+	if req.Domains != nil || req.Certificate != nil {
+		domains := []string(cert.Domains)
+		if req.Certificate != nil {
+			_, domains = a.certParseMeta(*req.Certificate)
+		} else {
+			domains = *req.Domains
+		}
+		if coverageIssues, err = a.certCheckCoverage(rctx, cert.ID, domains); err != nil {
+			a.l.Error("failed to check cert coverage", zap.Uint("id", id), zap.Error(err))
 			return a.er(c, http.StatusInternalServerError)
+		} else if siteCoverageHasError(coverageIssues) {
+			return a.erCertValidation(c, siteCoverageValidationErrors(coverageIssues))
 		}
 	}
 
+	// 如果证书部分发生变更，需要清理旧缓存（已知私钥会随着证书变化，所以没必要单独验证）
+	isCertChanged := req.Certificate != nil && *req.Certificate != cert.Certificate ||
+		req.IntermediateCertificate != nil && *req.IntermediateCertificate != cert.IntermediateCertificate
+	isCACertStatusChanged := (req.IntermediateCertificate == nil || *req.IntermediateCertificate == "") != // 新 CA 证书为空
+		(cert.IntermediateCertificate == "") // 旧 CA 证书为空
+
 	// 更新
+	isMaterialChanged := req.Certificate != nil && *req.Certificate != cert.Certificate ||
+		req.PrivateKey != nil ||
+		req.IntermediateCertificate != nil && *req.IntermediateCertificate != cert.IntermediateCertificate
+	previous := cert
+	previousProvider := string(cert.Provider) // certMapFields 会复用 Provider 的底层数组，需要先复制出来
 	a.certMapFields(&req, &cert)
+	isProviderChanged := req.Provider != nil && string(cert.Provider) != previousProvider
 
-	// 更新信息
-	if err := a.db.WithContext(rctx).Updates(&cert).Error; err != nil {
-		a.l.Error("failed to update cert", zap.Any("cert", cert), zap.Error(err))
+	// 更新信息，证书内容变化时在同一个事务中记录版本
+	if err := a.db.WithContext(rctx).Transaction(func(tx *gorm.DB) error {
+		if isMaterialChanged {
+			if err := a.certSnapshotVersion(tx, &previous); err != nil {
+				return err
+			}
+		}
+		if err := tx.Updates(&cert).Error; err != nil {
+			return fmt.Errorf("failed to update cert: %w", err)
+		}
+		if isMaterialChanged {
+			return a.certSaveVersion(tx, &cert, constants.CertVersionSourceUpload)
+		}
+		return nil
+	}); err != nil {
+		a.l.Error("failed to update cert", zap.Uint("id", cert.ID), zap.Error(err))
 		return a.er(c, http.StatusInternalServerError)
 	}
 
@@ -294,15 +417,25 @@ func (a *App) CertInfoUpdate(c echo.Context, id uint) error {
 				return a.er(c, http.StatusInternalServerError)
 			}
 			cert.Provider = nil
+			isProviderChanged = true
 		} // 如果是手动模式变自动，会设置 provider 参数，就不用特判
 	}
 
+	// provider 决定了站点配置中是否需要转发 HTTP-01 验证请求，所以变更时同样需要清理
+	if isCertChanged || isProviderChanged {
+		if err := a.certUpdateClearCache(rctx, cert.ID, isCACertStatusChanged); err != nil {
+			a.l.Error("failed to clear cache", zap.Error(err))
+			return a.er(c, http.StatusInternalServerError)
+		}
+	}
+
 	return c.JSON(http.StatusOK, &admin.CertInfoWithID{
-		Id:           &cert.ID,
-		Name:         &cert.Name,
-		Domains:      (*[]string)(&cert.Domains),
-		ExpiresAt:    utils.P(cert.ExpiresAt.Unix()),
-		IsManualMode: utils.P(cert.Provider == nil),
+		Id:             &cert.ID,
+		Name:           &cert.Name,
+		Domains:        (*[]string)(&cert.Domains),
+		ExpiresAt:      utils.P(cert.ExpiresAt.Unix()),
+		IsManualMode:   utils.P(cert.Provider == nil),
+		CoverageIssues: siteCoverageMapIssues(coverageIssues),
 		// 其他字段不开放
 	})
 }
@@ -332,19 +465,26 @@ func (a *App) CertRenew(c echo.Context, id uint) error {
 		return a.er(c, http.StatusNotImplemented)
 	}
 
-	// todo: 调用 provider 处理
-
-	// 清理缓存
-	if err := a.certUpdateClearCache(rctx, cert.ID, false); err != nil {
-		a.l.Error("failed to clear cache", zap.Error(err))
+	// 加锁，避免与自动续期同时进行
+	lockKey := fmt.Sprintf(constants.LockKeyCertRenew, cert.ID)
+	lockToken, ok, err := a.lockAcquire(rctx, lockKey, constants.CertRenewLockTTL)
+	if err != nil {
+		a.l.Error("failed to acquire cert renew lock", zap.Uint("id", id), zap.Error(err))
 		return a.er(c, http.StatusInternalServerError)
+	} else if !ok {
+		return a.er(c, http.StatusConflict)
 	}
+	defer func() {
+		if err := a.lockRelease(context.WithoutCancel(rctx), lockKey, lockToken); err != nil {
+			a.l.Error("failed to release cert renew lock", zap.Uint("id", id), zap.Error(err))
+		}
+	}()
 
-	// 更新信息
-	//if err := a.db.WithContext(rctx).Model(&cert).Update("content", newContentBytes).Error; err != nil {
-	//	a.l.Error("failed to update cert", zap.Any("cert", cert), zap.Error(err))
-	//	return a.er(c,http.StatusInternalServerError)
-	//}
+	// 调用 provider 处理，并保存、清理缓存；签发可能需要几分钟，客户端断开时也要完成，避免已经签发的证书没有保存
+	if err := a.certRenewWithRecord(context.WithoutCancel(rctx), &cert, constants.CertRenewTriggerManual); err != nil {
+		a.l.Error("failed to renew cert", zap.Uint("id", id), zap.Error(err))
+		return a.er(c, http.StatusBadGateway)
+	}
 
 	return c.JSON(http.StatusOK, &admin.CertInfoWithID{
 		Id:        &cert.ID,
@@ -355,6 +495,55 @@ func (a *App) CertRenew(c echo.Context, id uint) error {
 	})
 }
 
+func (a *App) CertRenewHistory(c echo.Context, id uint, params admin.CertRenewHistoryParams) error {
+	// 抓取 user 信息（认证）
+	err, statusCode := a.authAdmin(c, false, nil)
+	if err != nil {
+		a.l.Error("failed to auth", zap.Error(err))
+		return a.er(c, statusCode)
+	}
+
+	rctx := c.Request().Context()
+
+	var (
+		records      []models.CertRenewRecord
+		recordsCount int64
+	)
+
+	showAll, page, limit := a.parsePagination(params.Page, params.Limit)
+	queryBase := a.db.WithContext(rctx).Model(&models.CertRenewRecord{}).Where("cert_id = ?", id).Order("id DESC")
+	if !showAll {
+		queryBase = queryBase.Limit(limit).Offset(page * limit)
+	}
+
+	if err := queryBase.Find(&records).Error; err != nil {
+		a.l.Error("failed to get cert renew records", zap.Uint("id", id), zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+	if err := a.db.WithContext(rctx).Model(&models.CertRenewRecord{}).Where("cert_id = ?", id).Count(&recordsCount).Error; err != nil {
+		a.l.Error("failed to count cert renew records", zap.Uint("id", id), zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+
+	resRecords := []admin.CertRenewRecord{}
+	for _, record := range records {
+		resRecords = append(resRecords, admin.CertRenewRecord{
+			Id:         &record.ID,
+			Trigger:    &record.Trigger,
+			StartedAt:  utils.P(record.StartedAt.Unix()),
+			FinishedAt: utils.P(record.FinishedAt.Unix()),
+			Success:    &record.Success,
+			Error:      &record.Error,
+		})
+	}
+
+	return c.JSON(http.StatusOK, &admin.CertRenewHistoryResponse{
+		Limit:   &limit,
+		PageMax: utils.P(a.calcMaxPage(recordsCount, showAll, limit)),
+		List:    &resRecords,
+	})
+}
+
 func (a *App) CertDelete(c echo.Context, id uint) error {
 	// 抓取 user 信息（认证）
 	err, statusCode := a.authAdmin(c, true, nil)
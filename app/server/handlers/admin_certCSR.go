@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"caddy-delivery-network/app/server/certutil"
+	"caddy-delivery-network/app/server/gen/oapi/admin"
+	"caddy-delivery-network/app/server/models"
+	"caddy-delivery-network/app/server/utils"
+	"crypto/x509/pkix"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+)
+
+func (a *App) CertCsrGenerate(c echo.Context) error {
+	// 抓取 user 信息（认证）
+	err, statusCode := a.authAdmin(c, true, nil)
+	if err != nil {
+		a.l.Error("failed to auth", zap.Error(err))
+		return a.er(c, statusCode)
+	}
+
+	rctx := c.Request().Context()
+
+	// 绑定请求体
+	var req admin.CertCsrGenerateJSONRequestBody
+	if err = c.Bind(&req); err != nil {
+		a.l.Error("failed to bind request", zap.Error(err))
+		return a.er(c, http.StatusBadRequest)
+	}
+
+	var domains []string
+	for _, domain := range req.Domains {
+		if domain = strings.TrimSpace(domain); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	if len(domains) == 0 {
+		return a.er(c, http.StatusBadRequest)
+	}
+
+	// 生成私钥
+	var keyType certutil.KeyType
+	if req.KeyType != nil {
+		keyType = certutil.KeyType(*req.KeyType)
+	}
+	key, err := certutil.GenerateKey(keyType)
+	if err != nil {
+		a.l.Error("failed to generate key", zap.Error(err))
+		return a.er(c, http.StatusBadRequest)
+	}
+
+	// 生成签发请求
+	var subject pkix.Name
+	for _, field := range []struct {
+		value  *string
+		target *[]string
+	}{
+		{req.Organization, &subject.Organization},
+		{req.OrganizationalUnit, &subject.OrganizationalUnit},
+		{req.Country, &subject.Country},
+		{req.Province, &subject.Province},
+		{req.Locality, &subject.Locality},
+	} {
+		if field.value != nil && *field.value != "" {
+			*field.target = []string{*field.value}
+		}
+	}
+	csrDER, err := certutil.CreateCSR(domains, key, subject)
+	if err != nil {
+		a.l.Error("failed to create csr", zap.Error(err))
+		return a.er(c, http.StatusBadRequest)
+	}
+
+	keyPEM, err := certutil.EncodePrivateKeyPEM(key)
+	if err != nil {
+		a.l.Error("failed to encode key", zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+
+	// 创建（手动模式，等待上传签发后的证书）
+	cert := models.Cert{
+		Name:    domains[0],
+		Domains: domains,
+		CSR:     certutil.EncodeCSRPEM(csrDER),
+	}
+	if req.Name != nil && *req.Name != "" {
+		cert.Name = *req.Name
+	}
+	if cert.PrivateKey, err = a.aesEncrypt(keyPEM); err != nil {
+		a.l.Error("failed to encrypt private key", zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+
+	if err := a.db.WithContext(rctx).Create(&cert).Error; err != nil {
+		a.l.Error("failed to create cert", zap.Any("cert", cert), zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusCreated, &admin.CertInfoWithID{
+		Id:           &cert.ID,
+		Name:         &cert.Name,
+		Domains:      (*[]string)(&cert.Domains),
+		Csr:          &cert.CSR,
+		IsManualMode: utils.P(true),
+		// 其他字段不开放
+	})
+}
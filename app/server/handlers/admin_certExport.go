@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"caddy-delivery-network/app/server/certutil"
+	"caddy-delivery-network/app/server/gen/oapi/admin"
+	"caddy-delivery-network/app/server/models"
+	"caddy-delivery-network/app/server/utils"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+func (a *App) CertExport(c echo.Context, id uint) error {
+	// 抓取 user 信息（认证）
+	err, statusCode := a.authAdmin(c, true, nil)
+	if err != nil {
+		a.l.Error("failed to get user", zap.Error(err))
+		return a.er(c, statusCode)
+	}
+	jwtUser, err := a.getJwtUser(c)
+	if err != nil {
+		a.l.Error("failed to get user", zap.Error(err))
+		return a.er(c, http.StatusUnauthorized)
+	}
+
+	rctx := c.Request().Context()
+
+	// 绑定请求体
+	var req admin.CertExportJSONRequestBody
+	if err = c.Bind(&req); err != nil {
+		a.l.Error("failed to bind request", zap.Error(err))
+		return a.er(c, http.StatusBadRequest)
+	}
+	if req.Format == admin.Pkcs12 && (req.Password == nil || *req.Password == "") {
+		return a.er(c, http.StatusBadRequest)
+	}
+
+	// 从数据库中获得
+	var cert models.Cert
+	if err := a.db.WithContext(rctx).First(&cert, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return a.er(c, http.StatusNotFound)
+		} else {
+			a.l.Error("failed to get cert", zap.Uint("id", id), zap.Error(err))
+			return a.er(c, http.StatusInternalServerError)
+		}
+	}
+
+	if cert.Certificate == "" || cert.PrivateKey == nil {
+		return a.er(c, http.StatusConflict)
+	}
+
+	keyPEM, err := a.aesDecrypt(cert.PrivateKey)
+	if err != nil {
+		a.l.Error("failed to decrypt private key", zap.Uint("id", id), zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+
+	// 按格式打包
+	var (
+		content     []byte
+		filename    string
+		contentType string
+	)
+	fullchain := certutil.JoinPEM(cert.Certificate, cert.IntermediateCertificate)
+	switch req.Format {
+	case admin.Pem:
+		content = []byte(certutil.JoinPEM(fullchain, string(keyPEM)))
+		filename = fmt.Sprintf("cert-%d.pem", cert.ID)
+		contentType = "application/x-pem-file"
+	case admin.Zip:
+		files := []certutil.File{
+			{Name: "cert.pem", Content: []byte(certutil.JoinPEM(cert.Certificate))},
+			{Name: "fullchain.pem", Content: []byte(fullchain)},
+			{Name: "privkey.pem", Content: keyPEM},
+		}
+		if cert.IntermediateCertificate != "" {
+			files = append(files, certutil.File{Name: "chain.pem", Content: []byte(certutil.JoinPEM(cert.IntermediateCertificate))})
+		}
+		if content, err = certutil.EncodeZip(files); err != nil {
+			a.l.Error("failed to encode zip", zap.Uint("id", id), zap.Error(err))
+			return a.er(c, http.StatusInternalServerError)
+		}
+		filename = fmt.Sprintf("cert-%d.zip", cert.ID)
+		contentType = "application/zip"
+	case admin.Pkcs12:
+		if content, err = certutil.EncodePKCS12(cert.Certificate, string(keyPEM), cert.IntermediateCertificate, *req.Password); err != nil {
+			a.l.Error("failed to encode pkcs12", zap.Uint("id", id), zap.Error(err))
+			return a.er(c, http.StatusInternalServerError)
+		}
+		filename = fmt.Sprintf("cert-%d.p12", cert.ID)
+		contentType = "application/x-pkcs12"
+	default:
+		return a.er(c, http.StatusBadRequest)
+	}
+
+	// 记录导出操作，记录失败时不允许导出
+	record := models.CertExportRecord{
+		CertID: cert.ID,
+		UserID: jwtUser.ID,
+		Format: string(req.Format),
+		IP:     c.RealIP(),
+	}
+	if err := a.db.WithContext(rctx).Create(&record).Error; err != nil {
+		a.l.Error("failed to save cert export record", zap.Any("record", record), zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+
+	a.l.Info("cert exported", zap.Uint("id", cert.ID), zap.Uint("user", jwtUser.ID), zap.String("format", record.Format))
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	return c.Blob(http.StatusOK, contentType, content)
+}
+
+func (a *App) CertExportHistory(c echo.Context, id uint, params admin.CertExportHistoryParams) error {
+	// 抓取 user 信息（认证）
+	err, statusCode := a.authAdmin(c, true, nil)
+	if err != nil {
+		a.l.Error("failed to auth", zap.Error(err))
+		return a.er(c, statusCode)
+	}
+
+	rctx := c.Request().Context()
+
+	var (
+		records      []models.CertExportRecord
+		recordsCount int64
+	)
+
+	showAll, page, limit := a.parsePagination(params.Page, params.Limit)
+	queryBase := a.db.WithContext(rctx).Model(&models.CertExportRecord{}).Where("cert_id = ?", id).Order("id DESC")
+	if !showAll {
+		queryBase = queryBase.Limit(limit).Offset(page * limit)
+	}
+
+	if err := queryBase.Find(&records).Error; err != nil {
+		a.l.Error("failed to get cert export records", zap.Uint("id", id), zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+	if err := a.db.WithContext(rctx).Model(&models.CertExportRecord{}).Where("cert_id = ?", id).Count(&recordsCount).Error; err != nil {
+		a.l.Error("failed to count cert export records", zap.Uint("id", id), zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+
+	resRecords := []admin.CertExportRecord{}
+	for _, record := range records {
+		resRecords = append(resRecords, admin.CertExportRecord{
+			Id:         &record.ID,
+			UserId:     &record.UserID,
+			Format:     &record.Format,
+			Ip:         &record.IP,
+			ExportedAt: utils.P(record.CreatedAt.Unix()),
+		})
+	}
+
+	return c.JSON(http.StatusOK, &admin.CertExportHistoryResponse{
+		Limit:   &limit,
+		PageMax: utils.P(a.calcMaxPage(recordsCount, showAll, limit)),
+		List:    &resRecords,
+	})
+}
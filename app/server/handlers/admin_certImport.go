@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"caddy-delivery-network/app/server/certutil"
+	"caddy-delivery-network/app/server/constants"
+	"caddy-delivery-network/app/server/gen/oapi/admin"
+	"caddy-delivery-network/app/server/models"
+	"caddy-delivery-network/app/server/utils"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+)
+
+// CertImportBody 生成代码里用的是 json ，无法处理 form ，所以只能在这里重新定义
+type CertImportBody struct {
+	Name     *string `form:"name"`
+	Password *string `form:"password"`
+}
+
+func (a *App) CertImport(c echo.Context) error {
+	// 抓取 user 信息（认证）
+	err, statusCode := a.authAdmin(c, true, nil)
+	if err != nil {
+		a.l.Error("failed to auth", zap.Error(err))
+		return a.er(c, statusCode)
+	}
+
+	rctx := c.Request().Context()
+
+	// 绑定请求体
+	var req CertImportBody
+	if err = c.Bind(&req); err != nil {
+		a.l.Error("failed to bind request", zap.Error(err))
+		return a.er(c, http.StatusBadRequest)
+	}
+
+	contentFile, err := c.FormFile("content")
+	if err != nil {
+		a.l.Error("failed to load form file", zap.Error(err))
+		return a.er(c, http.StatusBadRequest)
+	}
+
+	f, err := contentFile.Open()
+	if err != nil {
+		a.l.Error("failed to open file", zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+	defer f.Close()
+
+	content, err := io.ReadAll(f)
+	if err != nil {
+		a.l.Error("failed to read file content", zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+
+	// 拆分证书、中间证书与私钥
+	var password string
+	if req.Password != nil {
+		password = *req.Password
+	}
+	bundle, err := certutil.ParseBundle(content, password)
+	if err != nil {
+		return a.erCertValidation(c, certutil.ValidationErrors{
+			{Field: "content", Code: certutil.ValidationInvalidBundle, Message: err.Error()},
+		})
+	}
+
+	keyPEM, err := certutil.EncodePrivateKeyPEM(bundle.Key)
+	if err != nil {
+		a.l.Error("failed to encode private key", zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+	var chain [][]byte
+	for _, ca := range bundle.Chain {
+		chain = append(chain, ca.Raw)
+	}
+
+	name := contentFile.Filename
+	if req.Name != nil && *req.Name != "" {
+		name = *req.Name
+	}
+	certInput := admin.CertInfoInput{
+		Name:                    &name,
+		Certificate:             utils.P(certutil.EncodeCertificatesPEM(bundle.Leaf.Raw)),
+		IntermediateCertificate: utils.P(certutil.EncodeCertificatesPEM(chain...)),
+		PrivateKey:              utils.P(string(keyPEM)),
+	}
+
+	// 与直接上传一样检查证书内容
+	var cert models.Cert
+	if validationErrs, err := a.certValidate(&certInput, &cert); err != nil {
+		a.l.Error("failed to validate cert", zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	} else if len(validationErrs) > 0 {
+		return a.erCertValidation(c, validationErrs)
+	}
+
+	// 创建（域名与过期时间从叶子证书中读取）
+	a.certMapFields(&certInput, &cert)
+
+	if err := a.db.WithContext(rctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&cert).Error; err != nil {
+			return fmt.Errorf("failed to create cert: %w", err)
+		}
+		return a.certSaveVersion(tx, &cert, constants.CertVersionSourceImport)
+	}); err != nil {
+		a.l.Error("failed to create cert", zap.Any("cert", cert), zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusCreated, &admin.CertInfoWithID{
+		Id:        &cert.ID,
+		Name:      &cert.Name,
+		Domains:   (*[]string)(&cert.Domains),
+		ExpiresAt: utils.P(cert.ExpiresAt.Unix()),
+		// 其他字段不开放
+	})
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"caddy-delivery-network/app/server/certutil"
+	"caddy-delivery-network/app/server/constants"
+	"caddy-delivery-network/app/server/gen/oapi/admin"
+	"caddy-delivery-network/app/server/models"
+	"caddy-delivery-network/app/server/utils"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"net/http"
+	"time"
+)
+
+func (a *App) CertVersionList(c echo.Context, id uint, params admin.CertVersionListParams) error {
+	// 抓取 user 信息（认证）
+	err, statusCode := a.authAdmin(c, false, nil)
+	if err != nil {
+		a.l.Error("failed to auth", zap.Error(err))
+		return a.er(c, statusCode)
+	}
+
+	rctx := c.Request().Context()
+
+	// 用于标记当前使用的版本
+	var cert models.Cert
+	if err := a.db.WithContext(rctx).Select("id", "certificate").First(&cert, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return a.er(c, http.StatusNotFound)
+		} else {
+			a.l.Error("failed to get cert", zap.Uint("id", id), zap.Error(err))
+			return a.er(c, http.StatusInternalServerError)
+		}
+	}
+
+	var (
+		versions      []models.CertVersion
+		versionsCount int64
+	)
+
+	showAll, page, limit := a.parsePagination(params.Page, params.Limit)
+	queryBase := a.db.WithContext(rctx).Model(&models.CertVersion{}).Where("cert_id = ?", id).Order("version DESC")
+	if !showAll {
+		queryBase = queryBase.Limit(limit).Offset(page * limit)
+	}
+
+	if err := queryBase.Find(&versions).Error; err != nil {
+		a.l.Error("failed to get cert versions", zap.Uint("id", id), zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+	if err := a.db.WithContext(rctx).Model(&models.CertVersion{}).Where("cert_id = ?", id).Count(&versionsCount).Error; err != nil {
+		a.l.Error("failed to count cert versions", zap.Uint("id", id), zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+
+	resVersions := []admin.CertVersion{}
+	for _, version := range versions {
+		resVersions = append(resVersions, admin.CertVersion{
+			Id:        &version.ID,
+			Version:   &version.Version,
+			Source:    &version.Source,
+			Issuer:    &version.Issuer,
+			Serial:    &version.Serial,
+			Domains:   (*[]string)(&version.Domains),
+			NotBefore: utils.P(version.NotBefore.Unix()),
+			NotAfter:  utils.P(version.NotAfter.Unix()),
+			CreatedAt: utils.P(version.CreatedAt.Unix()),
+			IsCurrent: utils.P(cert.Certificate != "" && version.Certificate == cert.Certificate),
+		})
+	}
+
+	return c.JSON(http.StatusOK, &admin.CertVersionListResponse{
+		Limit:   &limit,
+		PageMax: utils.P(a.calcMaxPage(versionsCount, showAll, limit)),
+		List:    &resVersions,
+	})
+}
+
+func (a *App) CertRollback(c echo.Context, id uint) error {
+	// 抓取 user 信息（认证）
+	err, statusCode := a.authAdmin(c, true, nil)
+	if err != nil {
+		a.l.Error("failed to get user", zap.Error(err))
+		return a.er(c, statusCode)
+	}
+
+	rctx := c.Request().Context()
+
+	// 绑定请求体
+	var req admin.CertRollbackJSONRequestBody
+	if err = c.Bind(&req); err != nil {
+		a.l.Error("failed to bind request", zap.Error(err))
+		return a.er(c, http.StatusBadRequest)
+	}
+
+	// 加锁，避免与续期同时进行
+	lockKey := fmt.Sprintf(constants.LockKeyCertRenew, id)
+	lockToken, ok, err := a.lockAcquire(rctx, lockKey, constants.CertRenewLockTTL)
+	if err != nil {
+		a.l.Error("failed to acquire cert renew lock", zap.Uint("id", id), zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	} else if !ok {
+		return a.er(c, http.StatusConflict)
+	}
+	defer func() {
+		if err := a.lockRelease(context.WithoutCancel(rctx), lockKey, lockToken); err != nil {
+			a.l.Error("failed to release cert renew lock", zap.Uint("id", id), zap.Error(err))
+		}
+	}()
+
+	// 从数据库中获得
+	var cert models.Cert
+	if err := a.db.WithContext(rctx).First(&cert, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return a.er(c, http.StatusNotFound)
+		} else {
+			a.l.Error("failed to get cert", zap.Uint("id", id), zap.Error(err))
+			return a.er(c, http.StatusInternalServerError)
+		}
+	}
+	var version models.CertVersion
+	if err := a.db.WithContext(rctx).First(&version, "id = ? AND cert_id = ?", req.VersionId, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return a.er(c, http.StatusNotFound)
+		} else {
+			a.l.Error("failed to get cert version", zap.Uint("id", id), zap.Uint("version", req.VersionId), zap.Error(err))
+			return a.er(c, http.StatusInternalServerError)
+		}
+	}
+
+	// 旧版本需要重新检查：可能已经过期，也可能不再覆盖站点现在的域名
+	now := time.Now()
+	if version.NotAfter.Before(now) {
+		return a.erCertValidation(c, certutil.ValidationErrors{{
+			Field:   "certificate",
+			Code:    certutil.ValidationExpired,
+			Message: fmt.Sprintf("certificate expired at %s", version.NotAfter.Format(time.RFC3339)),
+		}})
+	}
+	var privateKey string
+	if version.PrivateKey != nil {
+		keyPEM, err := a.aesDecrypt(version.PrivateKey)
+		if err != nil {
+			a.l.Error("failed to decrypt private key", zap.Uint("id", id), zap.Uint("version", req.VersionId), zap.Error(err))
+			return a.er(c, http.StatusInternalServerError)
+		}
+		privateKey = string(keyPEM)
+	}
+	if validationErrs := certutil.Validate(version.Certificate, privateKey, version.IntermediateCertificate, now); len(validationErrs) > 0 {
+		return a.erCertValidation(c, validationErrs)
+	}
+	coverageIssues, err := a.certCheckCoverage(rctx, cert.ID, version.Domains)
+	if err != nil {
+		a.l.Error("failed to check cert coverage", zap.Uint("id", id), zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	} else if siteCoverageHasError(coverageIssues) {
+		return a.erCertValidation(c, siteCoverageValidationErrors(coverageIssues))
+	}
+
+	// 恢复内容，中间证书可能变为空，所以需要用 Select 强制更新
+	hadIntermediate := cert.IntermediateCertificate != ""
+	previous := cert
+	cert.Certificate = version.Certificate
+	cert.IntermediateCertificate = version.IntermediateCertificate
+	cert.PrivateKey = version.PrivateKey
+	cert.CSR = version.CSR
+	cert.ExpiresAt = version.NotAfter
+	cert.Domains = version.Domains
+	// 回滚本身也是一个新版本，历史记录不会被修改
+	if err := a.db.WithContext(rctx).Transaction(func(tx *gorm.DB) error {
+		if err := a.certSnapshotVersion(tx, &previous); err != nil {
+			return err
+		}
+		if err := tx.
+			Model(&cert).
+			Select("certificate", "intermediate_certificate", "private_key", "csr", "expires_at", "domains").
+			Updates(&cert).Error; err != nil {
+			return fmt.Errorf("failed to update cert: %w", err)
+		}
+		return a.certSaveVersion(tx, &cert, constants.CertVersionSourceRollback)
+	}); err != nil {
+		a.l.Error("failed to update cert", zap.Uint("id", id), zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+
+	// 清理缓存，让实例重新部署
+	if err := a.certUpdateClearCache(rctx, cert.ID, hadIntermediate != (cert.IntermediateCertificate != "")); err != nil {
+		a.l.Error("failed to clear cache", zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+
+	a.l.Info("cert rolled back", zap.Uint("id", id), zap.Uint("version", version.Version))
+
+	return c.JSON(http.StatusOK, &admin.CertInfoWithID{
+		Id:             &cert.ID,
+		Name:           &cert.Name,
+		Domains:        (*[]string)(&cert.Domains),
+		ExpiresAt:      utils.P(cert.ExpiresAt.Unix()),
+		IsManualMode:   utils.P(cert.Provider == nil),
+		CoverageIssues: siteCoverageMapIssues(coverageIssues),
+		// 其他字段不开放
+	})
+}
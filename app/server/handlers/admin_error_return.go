@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"caddy-delivery-network/app/server/certutil"
 	"caddy-delivery-network/app/server/gen/oapi/admin"
 	"caddy-delivery-network/app/server/utils"
 	"github.com/labstack/echo/v4"
@@ -12,3 +13,26 @@ func (a *App) er(c echo.Context, statusCode int) error {
 		Message: utils.P(http.StatusText(statusCode)),
 	})
 }
+
+func (a *App) erCertValidation(c echo.Context, errs certutil.ValidationErrors) error {
+	resErrs := []admin.CertValidationError{}
+	for _, e := range errs {
+		resErrs = append(resErrs, admin.CertValidationError{
+			Field:   utils.P(e.Field),
+			Code:    utils.P(e.Code),
+			Message: utils.P(e.Message),
+		})
+	}
+
+	return c.JSON(http.StatusBadRequest, &admin.CertValidationErrorMessage{
+		Message: utils.P(http.StatusText(http.StatusBadRequest)),
+		Errors:  &resErrs,
+	})
+}
+
+func (a *App) erSiteCoverage(c echo.Context, issues []siteCoverageIssue) error {
+	return c.JSON(http.StatusBadRequest, &admin.SiteCoverageErrorMessage{
+		Message: utils.P(http.StatusText(http.StatusBadRequest)),
+		Issues:  siteCoverageMapIssues(issues),
+	})
+}
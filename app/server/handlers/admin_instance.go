@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"bytes"
 	"caddy-delivery-network/app/server/constants"
 	"caddy-delivery-network/app/server/gen/oapi/admin"
+	"caddy-delivery-network/app/server/gen/oapi/worker"
 	"caddy-delivery-network/app/server/models"
+	"caddy-delivery-network/app/server/types"
 	"caddy-delivery-network/app/server/utils"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
@@ -14,6 +18,8 @@ import (
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 	"net/http"
+	"path"
+	"strings"
 )
 
 func (a *App) instanceGetLastSeen(ctx context.Context, isManualMode bool, id uint) *int64 {
@@ -32,6 +38,132 @@ func (a *App) instanceGetLastSeen(ctx context.Context, isManualMode bool, id uin
 	return nil
 }
 
+func (a *App) instanceGetSyncReport(ctx context.Context, instance *models.Instance) *admin.InstanceSyncReport {
+	if instance.IsManualMode {
+		return nil
+	}
+
+	// 获取实例上报的同步结果
+	cacheKey := fmt.Sprintf(constants.CacheKeyInstanceReport, instance.ID)
+	reportBytes, err := a.rdb.Get(ctx, cacheKey).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			a.l.Error("failed to get instance sync report", zap.Uint("id", instance.ID), zap.Error(err))
+		}
+		return nil
+	}
+	var cached types.CacheInstanceReport
+	if err := json.Unmarshal(reportBytes, &cached); err != nil {
+		a.l.Error("failed to unmarshal instance sync report", zap.Uint("id", instance.ID), zap.Error(err))
+		return nil
+	}
+
+	report := cached.Report
+	res := &admin.InstanceSyncReport{
+		ReceivedAt:        &cached.ReceivedAt,
+		SyncedAt:          report.SyncedAt,
+		Status:            &report.Status,
+		Error:             report.Error,
+		ConfigDigest:      &report.ConfigDigest,
+		CaddyLoadResponse: report.CaddyLoadResponse,
+		CaddyVersion:      report.CaddyVersion,
+		WorkerVersion:     report.WorkerVersion,
+	}
+	if report.Files != nil {
+		files := []admin.InstanceFileSyncResult{}
+		for _, file := range *report.Files {
+			files = append(files, admin.InstanceFileSyncResult{
+				Path:   &file.Path,
+				Digest: file.Digest,
+				Status: &file.Status,
+				Error:  file.Error,
+			})
+		}
+		res.Files = &files
+	}
+	if report.Diagnostics != nil {
+		res.Diagnostics = a.instanceMapDiagnostics(ctx, *report.Diagnostics)
+	}
+	res.LastDrift = a.instanceGetLastDrift(ctx, instance.ID)
+
+	// 与当前应该加载的配置比较，判断实例是否落后
+	if configBytes, err := a.getInstanceConfig(ctx, instance); err != nil {
+		a.l.Error("failed to get instance config", zap.Uint("id", instance.ID), zap.Error(err))
+	} else {
+		res.ExpectedConfigDigest = utils.P(utils.Digest(configBytes))
+		res.ConfigInSync = utils.P(*res.ExpectedConfigDigest == report.ConfigDigest)
+	}
+
+	return res
+}
+
+// instanceGetLastDrift 获取实例最近一次上报的配置漂移
+func (a *App) instanceGetLastDrift(ctx context.Context, id uint) *admin.InstanceDriftEvent {
+	cacheKey := fmt.Sprintf(constants.CacheKeyInstanceDrift, id)
+	driftBytes, err := a.rdb.Get(ctx, cacheKey).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			a.l.Error("failed to get instance drift", zap.Uint("id", id), zap.Error(err))
+		}
+		return nil
+	}
+	var cached types.CacheInstanceReport
+	if err := json.Unmarshal(driftBytes, &cached); err != nil || cached.Report.Drift == nil {
+		a.l.Error("failed to unmarshal instance drift", zap.Uint("id", id), zap.Error(err))
+		return nil
+	}
+
+	drift := cached.Report.Drift
+	return &admin.InstanceDriftEvent{
+		ReceivedAt:  &cached.ReceivedAt,
+		DetectedAt:  &drift.DetectedAt,
+		Differences: &drift.Differences,
+		Reapplied:   &drift.Reapplied,
+		Error:       drift.Error,
+	}
+}
+
+// instanceMapDiagnostics 转换实例上报的配置诊断信息，并补充站点名称
+func (a *App) instanceMapDiagnostics(ctx context.Context, diagnostics []worker.ConfigDiagnostic) *[]admin.InstanceConfigDiagnostic {
+	// 查询涉及的站点名称
+	var siteIDs []uint
+	for _, d := range diagnostics {
+		if d.SiteId != nil {
+			siteIDs = append(siteIDs, *d.SiteId)
+		}
+	}
+	siteNames := make(map[uint]string)
+	if len(siteIDs) > 0 {
+		var sites []models.Site
+		if err := a.db.WithContext(ctx).Select("id", "name").Find(&sites, "id IN ?", siteIDs).Error; err != nil {
+			a.l.Error("failed to get sites", zap.Error(err))
+		}
+		for _, site := range sites {
+			siteNames[site.ID] = site.Name
+		}
+	}
+
+	res := []admin.InstanceConfigDiagnostic{}
+	for _, d := range diagnostics {
+		item := admin.InstanceConfigDiagnostic{
+			Severity:    &d.Severity,
+			Message:     &d.Message,
+			Directive:   d.Directive,
+			Line:        d.Line,
+			Section:     d.Section,
+			SiteId:      d.SiteId,
+			SectionLine: d.SectionLine,
+		}
+		if d.SiteId != nil {
+			if name, ok := siteNames[*d.SiteId]; ok {
+				item.SiteName = &name
+			}
+		}
+		res = append(res, item)
+	}
+	return &res
+}
+
 func (a *App) instanceMapFields(req *admin.InstanceInfoInput, instance *models.Instance) {
 	if req.Name != nil {
 		instance.Name = *req.Name
@@ -48,6 +180,9 @@ func (a *App) instanceMapFields(req *admin.InstanceInfoInput, instance *models.I
 	if req.SiteIds != nil {
 		instance.SiteIDs = utils.UintArray2int64(*req.SiteIds)
 	}
+	if req.CaIds != nil {
+		instance.CAIDs = utils.UintArray2int64(*req.CaIds)
+	}
 }
 
 func (a *App) instanceValidate(ctx context.Context, instance *models.Instance) (error, int) {
@@ -76,6 +211,12 @@ func (a *App) instanceValidate(ctx context.Context, instance *models.Instance) (
 		return err, statusCode
 	}
 
+	// 检查 ca ids
+	if err, statusCode := validateIDs[models.CertAuthority](a.db.WithContext(ctx), utils.Int64Array2uint(instance.CAIDs)); err != nil {
+		a.l.Error("failed to validate ca", zap.Error(err))
+		return err, statusCode
+	}
+
 	return nil, http.StatusOK
 }
 
@@ -88,6 +229,9 @@ func (a *App) instanceUpdateClearDataCache(ctx context.Context, id uint) {
 
 	// 清理文件列表缓存
 	a.rdb.Del(ctx, fmt.Sprintf(constants.CacheKeyInstanceFiles, id))
+
+	// 通知实例立即同步
+	a.instanceNotify(ctx, id)
 }
 
 func (a *App) instanceUpdateClearAuthCache(ctx context.Context, id uint) {
@@ -137,6 +281,7 @@ func (a *App) InstanceCreate(c echo.Context) error {
 		IsManualMode:      &instance.IsManualMode,
 		AdditionalFileIds: utils.P(utils.Int64Array2uint(instance.AdditionalFileIDs)),
 		SiteIds:           utils.P(utils.Int64Array2uint(instance.SiteIDs)),
+		CaIds:             utils.P(utils.Int64Array2uint(instance.CAIDs)),
 	})
 }
 
@@ -177,6 +322,7 @@ func (a *App) InstanceList(c echo.Context, params admin.InstanceListParams) erro
 			Name:         &instance.Name,
 			IsManualMode: &instance.IsManualMode,
 			LastSeen:     a.instanceGetLastSeen(rctx, instance.IsManualMode, instance.ID),
+			SyncReport:   a.instanceGetSyncReport(rctx, &instance),
 		})
 	}
 
@@ -215,7 +361,9 @@ func (a *App) InstanceInfoGet(c echo.Context, id uint) error {
 		IsManualMode:      &instance.IsManualMode,
 		AdditionalFileIds: utils.P(utils.Int64Array2uint(instance.AdditionalFileIDs)),
 		SiteIds:           utils.P(utils.Int64Array2uint(instance.SiteIDs)),
+		CaIds:             utils.P(utils.Int64Array2uint(instance.CAIDs)),
 		LastSeen:          a.instanceGetLastSeen(rctx, instance.IsManualMode, instance.ID),
+		SyncReport:        a.instanceGetSyncReport(rctx, &instance),
 	})
 }
 
@@ -247,9 +395,6 @@ func (a *App) InstanceInfoUpdate(c echo.Context, id uint) error {
 		}
 	}
 
-	// 清理缓存
-	a.instanceUpdateClearDataCache(rctx, instance.ID)
-
 	// 更新信息
 	a.instanceMapFields(&req, &instance)
 
@@ -265,6 +410,8 @@ func (a *App) InstanceInfoUpdate(c echo.Context, id uint) error {
 		return a.er(c, http.StatusInternalServerError)
 	}
 
+	a.instanceUpdateClearDataCache(rctx, instance.ID)
+
 	return c.JSON(http.StatusOK, &admin.InstanceInfoWithID{
 		Id:                &instance.ID,
 		Name:              &instance.Name,
@@ -272,6 +419,7 @@ func (a *App) InstanceInfoUpdate(c echo.Context, id uint) error {
 		IsManualMode:      &instance.IsManualMode,
 		AdditionalFileIds: utils.P(utils.Int64Array2uint(instance.AdditionalFileIDs)),
 		SiteIds:           utils.P(utils.Int64Array2uint(instance.SiteIDs)),
+		CaIds:             utils.P(utils.Int64Array2uint(instance.CAIDs)),
 		LastSeen:          a.instanceGetLastSeen(rctx, instance.IsManualMode, instance.ID),
 	})
 }
@@ -315,6 +463,7 @@ func (a *App) InstanceRotateToken(c echo.Context, id uint) error {
 		IsManualMode:      &instance.IsManualMode,
 		AdditionalFileIds: utils.P(utils.Int64Array2uint(instance.AdditionalFileIDs)),
 		SiteIds:           utils.P(utils.Int64Array2uint(instance.SiteIDs)),
+		CaIds:             utils.P(utils.Int64Array2uint(instance.CAIDs)),
 		LastSeen:          a.instanceGetLastSeen(rctx, instance.IsManualMode, instance.ID),
 	})
 }
@@ -338,6 +487,111 @@ func (a *App) InstanceDelete(c echo.Context, id uint) error {
 	// 清理缓存
 	a.instanceUpdateClearDataCache(rctx, id)
 	a.instanceUpdateClearAuthCache(rctx, id)
+	a.rdb.Del(rctx, fmt.Sprintf(constants.CacheKeyInstanceReport, id))
+	a.rdb.Del(rctx, fmt.Sprintf(constants.CacheKeyInstanceDrift, id))
 
 	return c.NoContent(http.StatusOK)
 }
+
+func (a *App) InstanceExport(c echo.Context, id uint) error {
+	// 抓取 user 信息（认证），导出包中包含私钥，只有管理员可以操作
+	err, statusCode := a.authAdmin(c, true, nil)
+	if err != nil {
+		a.l.Error("failed to get user", zap.Error(err))
+		return a.er(c, statusCode)
+	}
+	jwtUser, err := a.getJwtUser(c)
+	if err != nil {
+		a.l.Error("failed to get user", zap.Error(err))
+		return a.er(c, http.StatusUnauthorized)
+	}
+
+	rctx := c.Request().Context()
+
+	// 从数据库中获得
+	var instance models.Instance
+	if err := a.db.WithContext(rctx).First(&instance, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return a.er(c, http.StatusNotFound)
+		} else {
+			a.l.Error("failed to get instance", zap.Uint("id", id), zap.Error(err))
+			return a.er(c, http.StatusInternalServerError)
+		}
+	}
+
+	// 准备配置与文件
+	config, err := a.buildInstanceConfigByModel(rctx, &instance)
+	if err != nil {
+		a.l.Error("failed to build instance config", zap.Uint("id", id), zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+	files, err := a.loadInstanceFiles(rctx, &instance)
+	if err != nil {
+		a.l.Error("failed to load instance files", zap.Uint("id", id), zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+
+	// 手动部署的实例使用默认的数据根目录
+	config = strings.ReplaceAll(config, constants.DataRootPlaceholder, constants.DataRootDefault)
+
+	// 文件按照实例上的路径存放（去掉开头的 / ），私钥只允许所有者读取
+	dataRoot := strings.TrimPrefix(constants.DataRootDefault, "/")
+	var buf bytes.Buffer
+	bw := newBundleWriter(&buf)
+	checksums := fmt.Sprintf("%s  %s\n", utils.Digest([]byte(config)), constants.ExportConfigName)
+	if err := bw.add(constants.ExportConfigName, []byte(config), 0644); err != nil {
+		a.l.Error("failed to write export config", zap.Uint("id", id), zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+	var records []models.CertExportRecord
+	for _, f := range files {
+		name := path.Join(dataRoot, f.Path)
+		mode := int64(0644)
+		if path.Base(f.Path) == constants.CertPathKeyName {
+			mode = 0600
+
+			// 每张包含私钥的证书都记录一次导出
+			var certID uint
+			if _, err := fmt.Sscanf(f.Path, constants.CertPathDir+constants.CertPathKeyName, &certID); err != nil {
+				a.l.Error("failed to parse cert id", zap.String("path", f.Path), zap.Error(err))
+				return a.er(c, http.StatusInternalServerError)
+			}
+			records = append(records, models.CertExportRecord{
+				CertID: certID,
+				UserID: jwtUser.ID,
+				Format: constants.ExportRecordFormat,
+				IP:     c.RealIP(),
+			})
+		}
+		if err := bw.add(name, f.Content, mode); err != nil {
+			a.l.Error("failed to write export file", zap.Uint("id", id), zap.String("path", f.Path), zap.Error(err))
+			return a.er(c, http.StatusInternalServerError)
+		}
+		checksums += fmt.Sprintf("%s  %s\n", f.Digest, name)
+	}
+	if err := bw.add(constants.ExportChecksumName, []byte(checksums), 0644); err != nil {
+		a.l.Error("failed to write export checksums", zap.Uint("id", id), zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+	if err := bw.add(constants.ExportInstallName, []byte(constants.ExportInstallScript), 0755); err != nil {
+		a.l.Error("failed to write export install script", zap.Uint("id", id), zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+	if err := bw.close(); err != nil {
+		a.l.Error("failed to close export", zap.Uint("id", id), zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+
+	// 记录导出操作，记录失败时不允许导出
+	if len(records) > 0 {
+		if err := a.db.WithContext(rctx).Create(&records).Error; err != nil {
+			a.l.Error("failed to save cert export records", zap.Uint("id", id), zap.Error(err))
+			return a.er(c, http.StatusInternalServerError)
+		}
+	}
+
+	a.l.Info("instance exported", zap.Uint("id", instance.ID), zap.Uint("user", jwtUser.ID), zap.Int("certs", len(records)))
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, fmt.Sprintf(constants.ExportFileName, instance.ID)))
+	return c.Blob(http.StatusOK, "application/gzip", buf.Bytes())
+}
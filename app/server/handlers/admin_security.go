@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"caddy-delivery-network/app/server/gen/oapi/admin"
+	"caddy-delivery-network/app/server/utils"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+func (a *App) reencryptMapStatus(status *reencryptStatus) *admin.ReencryptStatus {
+	res := &admin.ReencryptStatus{
+		State:      &status.State,
+		PrimaryKey: &status.PrimaryKey,
+		Total:      &status.Total,
+		Migrated:   &status.Migrated,
+		Failed:     &status.Failed,
+	}
+	if !status.StartedAt.IsZero() {
+		res.StartedAt = utils.P(status.StartedAt.Unix())
+	}
+	if !status.FinishedAt.IsZero() {
+		res.FinishedAt = utils.P(status.FinishedAt.Unix())
+	}
+	if status.Error != "" {
+		res.Error = &status.Error
+	}
+
+	return res
+}
+
+func (a *App) SecurityReencryptStatus(c echo.Context) error {
+	// 抓取 user 信息（认证）
+	err, statusCode := a.authAdmin(c, true, nil)
+	if err != nil {
+		a.l.Error("failed to auth", zap.Error(err))
+		return a.er(c, statusCode)
+	}
+
+	rctx := c.Request().Context()
+
+	status, err := a.reencryptGetStatus(rctx)
+	if err != nil {
+		a.l.Error("failed to get reencrypt status", zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusOK, a.reencryptMapStatus(status))
+}
+
+func (a *App) SecurityReencryptStart(c echo.Context) error {
+	// 抓取 user 信息（认证）
+	err, statusCode := a.authAdmin(c, true, nil)
+	if err != nil {
+		a.l.Error("failed to auth", zap.Error(err))
+		return a.er(c, statusCode)
+	}
+
+	rctx := c.Request().Context()
+
+	status, ok, err := a.reencryptStart(rctx)
+	if err != nil {
+		a.l.Error("failed to start reencrypt", zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	} else if !ok {
+		return a.er(c, http.StatusConflict)
+	}
+
+	return c.JSON(http.StatusAccepted, a.reencryptMapStatus(status))
+}
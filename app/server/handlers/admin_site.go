@@ -47,6 +47,16 @@ func (a *App) siteValidate(ctx context.Context, site *models.Site) (error, int)
 			a.l.Error("failed to validate cert", zap.Error(err))
 			return err, statusCode
 		}
+
+		// 还没有签发的手动证书（只生成了 CSR ）不能部署到站点上；
+		// 由 provider 管理的证书可以在首次签发前部署，首次签发的 HTTP-01 验证依赖站点配置
+		var cert models.Cert
+		if err := a.db.WithContext(ctx).Select("id", "certificate", "private_key", "provider").First(&cert, "id = ?", *site.CertID).Error; err != nil {
+			a.l.Error("failed to get cert", zap.Error(err))
+			return fmt.Errorf("failed to get cert: %w", err), http.StatusInternalServerError
+		} else if !certIsIssued(&cert) && cert.Provider == nil {
+			return fmt.Errorf("cert %d has not been issued yet", cert.ID), http.StatusBadRequest
+		}
 	}
 
 	return nil, http.StatusOK
@@ -71,6 +81,9 @@ func (a *App) siteUpdateClearCache(ctx context.Context, id uint) error {
 		// 可能涉及到证书变更，所以同时也清理掉心跳数据缓存和文件缓存
 		a.rdb.Del(ctx, fmt.Sprintf(constants.CacheKeyInstanceHeartbeat, instance.ID))
 		a.rdb.Del(ctx, fmt.Sprintf(constants.CacheKeyInstanceFiles, instance.ID))
+
+		// 通知实例立即同步
+		a.instanceNotify(ctx, instance.ID)
 	}
 
 	return nil
@@ -120,11 +133,25 @@ func (a *App) SiteCreate(c echo.Context) error {
 		return a.er(c, statusCode)
 	}
 
+	// 检查证书是否覆盖站点的域名
+	coverageIssues, err := a.siteCheckCoverageByModel(rctx, &site)
+	if err != nil {
+		a.l.Error("failed to check cert coverage", zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	} else if siteCoverageHasError(coverageIssues) {
+		return a.erSiteCoverage(c, coverageIssues)
+	}
+
 	if err := a.db.WithContext(rctx).Create(&site).Error; err != nil {
 		a.l.Error("failed to create site", zap.Any("site", site), zap.Error(err))
 		return a.er(c, http.StatusInternalServerError)
 	}
 
+	// 创建之前还没有 ID
+	for i := range coverageIssues {
+		coverageIssues[i].SiteID = site.ID
+	}
+
 	return c.JSON(http.StatusCreated, &admin.SiteInfoWithID{
 		Id:             &site.ID,
 		Name:           &site.Name,
@@ -132,6 +159,7 @@ func (a *App) SiteCreate(c echo.Context) error {
 		TemplateId:     &site.TemplateID,
 		TemplateValues: (*[]string)(&site.TemplateValues),
 		CertId:         site.CertID,
+		CoverageIssues: siteCoverageMapIssues(coverageIssues),
 	})
 }
 
@@ -240,12 +268,6 @@ func (a *App) SiteInfoUpdate(c echo.Context, id uint) error {
 		}
 	}
 
-	// 清理缓存
-	if err := a.siteUpdateClearCache(rctx, site.ID); err != nil {
-		a.l.Error("failed to clear cache", zap.Error(err))
-		return a.er(c, http.StatusInternalServerError)
-	}
-
 	// 更新
 	a.siteMapFields(&req, &site)
 
@@ -255,6 +277,19 @@ func (a *App) SiteInfoUpdate(c echo.Context, id uint) error {
 		return a.er(c, statusCode)
 	}
 
+	// 检查证书是否覆盖站点的域名（没有指定证书时会清理掉 cert 选项）
+	checkSite := site
+	if req.CertId == nil || *req.CertId == 0 {
+		checkSite.CertID = nil
+	}
+	coverageIssues, err := a.siteCheckCoverageByModel(rctx, &checkSite)
+	if err != nil {
+		a.l.Error("failed to check cert coverage", zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	} else if siteCoverageHasError(coverageIssues) {
+		return a.erSiteCoverage(c, coverageIssues)
+	}
+
 	// 更新信息
 	if err := a.db.WithContext(rctx).Updates(&site).Error; err != nil {
 		a.l.Error("failed to update site", zap.Any("site", site), zap.Error(err))
@@ -273,6 +308,11 @@ func (a *App) SiteInfoUpdate(c echo.Context, id uint) error {
 		} // 如果是无证书变有证书，会设置 cert 参数，就不用特判
 	}
 
+	if err := a.siteUpdateClearCache(rctx, site.ID); err != nil {
+		a.l.Error("failed to clear cache", zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+
 	return c.JSON(http.StatusOK, &admin.SiteInfoWithID{
 		Id:             &site.ID,
 		Name:           &site.Name,
@@ -280,6 +320,50 @@ func (a *App) SiteInfoUpdate(c echo.Context, id uint) error {
 		TemplateId:     &site.TemplateID,
 		TemplateValues: (*[]string)(&site.TemplateValues),
 		CertId:         site.CertID,
+		CoverageIssues: siteCoverageMapIssues(coverageIssues),
+	})
+}
+
+func (a *App) SiteCoverageReport(c echo.Context) error {
+	// 抓取 user 信息（认证）
+	err, statusCode := a.authAdmin(c, false, nil)
+	if err != nil {
+		a.l.Error("failed to auth", zap.Error(err))
+		return a.er(c, statusCode)
+	}
+
+	rctx := c.Request().Context()
+
+	// 只检查指定了证书的站点
+	var sites []models.Site
+	if err := a.db.WithContext(rctx).Order("id ASC").Find(&sites, "cert_id IS NOT NULL").Error; err != nil {
+		a.l.Error("failed to get sites", zap.Error(err))
+		return a.er(c, http.StatusInternalServerError)
+	}
+
+	var certIDs []uint
+	for _, site := range sites {
+		certIDs = append(certIDs, *site.CertID)
+	}
+	var certs []models.Cert
+	if len(certIDs) > 0 {
+		if err := a.db.WithContext(rctx).Select("id", "domains").Find(&certs, "id IN ?", certIDs).Error; err != nil {
+			a.l.Error("failed to get certs", zap.Error(err))
+			return a.er(c, http.StatusInternalServerError)
+		}
+	}
+	certDomains := make(map[uint][]string, len(certs))
+	for _, cert := range certs {
+		certDomains[cert.ID] = cert.Domains
+	}
+
+	var issues []siteCoverageIssue
+	for _, site := range sites {
+		issues = append(issues, siteCheckCoverage(&site, *site.CertID, certDomains[*site.CertID])...)
+	}
+
+	return c.JSON(http.StatusOK, &admin.SiteCoverageReportResponse{
+		List: siteCoverageMapIssues(issues),
 	})
 }
 
@@ -56,6 +56,9 @@ func (a *App) templateUpdateClearCache(ctx context.Context, id uint) error {
 			// 同 ID 模板更新不会涉及到文件变更，仅需清理配置和心跳数据缓存（心跳数据里包含了配置文件的更新时间）
 			a.rdb.Del(ctx, fmt.Sprintf(constants.CacheKeyInstanceConfig, instance.ID))
 			a.rdb.Del(ctx, fmt.Sprintf(constants.CacheKeyInstanceHeartbeat, instance.ID))
+
+			// 通知实例立即同步
+			a.instanceNotify(ctx, instance.ID)
 		}
 	}
 
@@ -219,13 +222,8 @@ func (a *App) TemplateInfoUpdate(c echo.Context, id uint) error {
 	}
 
 	// 如果模板发生变更，需要清理旧缓存（已知私钥会随着证书变化，所以没必要单独验证）
-	if req.Content != nil && *req.Content != template.Content ||
-		req.Variables != nil {
-		if err := a.templateUpdateClearCache(rctx, template.ID); err != nil {
-			a.l.Error("failed to clear cache", zap.Error(err))
-			return a.er(c, http.StatusInternalServerError)
-		}
-	}
+	isContentChanged := req.Content != nil && *req.Content != template.Content ||
+		req.Variables != nil
 
 	// 更新
 	a.templateMapFields(&req, &template)
@@ -236,6 +234,13 @@ func (a *App) TemplateInfoUpdate(c echo.Context, id uint) error {
 		return a.er(c, http.StatusInternalServerError)
 	}
 
+	if isContentChanged {
+		if err := a.templateUpdateClearCache(rctx, template.ID); err != nil {
+			a.l.Error("failed to clear cache", zap.Error(err))
+			return a.er(c, http.StatusInternalServerError)
+		}
+	}
+
 	return c.JSON(http.StatusOK, &admin.TemplateInfoWithID{
 		Id:          &template.ID,
 		Name:        &template.Name,
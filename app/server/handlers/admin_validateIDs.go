@@ -8,7 +8,7 @@ import (
 )
 
 // 方法不能有类型形参，所以这个不能用 (a *App)
-func validateIDs[M models.AdditionalFile | models.Site | models.Template | models.Cert](db *gorm.DB, ids []uint) (error, int) {
+func validateIDs[M models.AdditionalFile | models.Site | models.Template | models.Cert | models.CertAuthority](db *gorm.DB, ids []uint) (error, int) {
 	if len(ids) > 0 {
 		var (
 			count int64
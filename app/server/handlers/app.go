@@ -4,6 +4,7 @@ import (
 	"caddy-delivery-network/app/server/gen/oapi/admin"
 	"caddy-delivery-network/app/server/gen/oapi/worker"
 	"caddy-delivery-network/app/server/jwt"
+	"caddy-delivery-network/app/server/keyring"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -13,19 +14,29 @@ var _ admin.ServerInterface = (*App)(nil)
 var _ worker.ServerInterface = (*App)(nil)
 
 type App struct {
-	l   *zap.Logger   // 日志
-	db  *gorm.DB      // 数据库
-	rdb *redis.Client // Redis
-	jwt *jwt.JWT      // JWT ，用于无状态验证
-	esk []byte        // 加密用密钥 (EncryptSecretKey)
+	l   *zap.Logger      // 日志
+	db  *gorm.DB         // 数据库
+	rdb *redis.Client    // Redis
+	jwt *jwt.JWT         // JWT ，用于无状态验证
+	kr  *keyring.Keyring // 加密用密钥环 (EncryptSecretKeys)
+
+	publicEndpoint string // server 对外的访问地址，实例会把 ACME HTTP-01 验证请求转发到这里
+
+	notifier instanceNotifier // 连接在这个副本上的实例事件流
 }
 
-func NewApp(l *zap.Logger, db *gorm.DB, rdb *redis.Client, j *jwt.JWT, esk string) *App {
+func NewApp(l *zap.Logger, db *gorm.DB, rdb *redis.Client, j *jwt.JWT, kr *keyring.Keyring, publicEndpoint string) *App {
 	return &App{
 		l:   l,
 		db:  db,
 		rdb: rdb,
 		jwt: j,
-		esk: []byte(esk),
+		kr:  kr,
+
+		publicEndpoint: publicEndpoint,
+
+		notifier: instanceNotifier{
+			subs: make(map[uint]map[chan struct{}]struct{}),
+		},
 	}
 }
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"caddy-delivery-network/app/server/acme"
+	"caddy-delivery-network/app/server/constants"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// acmeHTTP01Solver 把验证内容存放在 Redis 里，这样无论 CA 访问到哪个实例、被转发到哪个 server 副本都能正确响应
+type acmeHTTP01Solver struct {
+	rdb *redis.Client
+}
+
+var _ acme.Solver = (*acmeHTTP01Solver)(nil)
+
+func (s *acmeHTTP01Solver) Type() string {
+	return acme.ChallengeTypeHTTP01
+}
+
+func (s *acmeHTTP01Solver) Present(ctx context.Context, ch *acme.Challenge) error {
+	return s.rdb.Set(ctx, fmt.Sprintf(constants.CacheKeyAcmeHTTP01, ch.Token), ch.KeyAuth, constants.CacheExpireAcmeHTTP01).Err()
+}
+
+func (s *acmeHTTP01Solver) CleanUp(ctx context.Context, ch *acme.Challenge) error {
+	return s.rdb.Del(ctx, fmt.Sprintf(constants.CacheKeyAcmeHTTP01, ch.Token)).Err()
+}
+
+func (a *App) AcmeHTTP01Challenge(c echo.Context) error {
+	rctx := c.Request().Context()
+
+	token := c.Param("token")
+	keyAuth, err := a.rdb.Get(rctx, fmt.Sprintf(constants.CacheKeyAcmeHTTP01, token)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			a.l.Error("failed to get acme http-01 challenge", zap.String("token", token), zap.Error(err))
+			return c.NoContent(http.StatusInternalServerError)
+		}
+		return c.NoContent(http.StatusNotFound)
+	}
+
+	return c.String(http.StatusOK, keyAuth)
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"archive/tar"
+	"caddy-delivery-network/app/server/constants"
+	"caddy-delivery-network/app/server/models"
+	"caddy-delivery-network/app/server/utils"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"io"
+	"sort"
+	"time"
+)
+
+type instanceFile struct {
+	Path    string
+	Content []byte
+	Digest  string
+}
+
+// loadInstanceFiles 一次性读取实例需要的所有文件，每种文件只查询一次数据库，按路径排序
+func (a *App) loadInstanceFiles(ctx context.Context, instance *models.Instance) ([]instanceFile, error) {
+	var files []instanceFile
+	add := func(path string, content []byte) {
+		files = append(files, instanceFile{
+			Path:    path,
+			Content: content,
+			Digest:  utils.Digest(content),
+		})
+	}
+
+	// 额外文件
+	if len(instance.AdditionalFileIDs) > 0 {
+		var aFiles []models.AdditionalFile
+		if err := a.db.WithContext(ctx).Find(&aFiles, "id IN ?", utils.Int64Array2uint(instance.AdditionalFileIDs)).Error; err != nil {
+			return nil, fmt.Errorf("failed to get additional files: %w", err)
+		}
+		for _, aFile := range aFiles {
+			add(constants.AFilePathPrefix+aFile.Filename, aFile.Content)
+		}
+	}
+
+	// 作为信任根的 CA 证书
+	if len(instance.CAIDs) > 0 {
+		var cas []models.CertAuthority
+		if err := a.db.WithContext(ctx).Select("id", "certificate").Find(&cas, "id IN ?", utils.Int64Array2uint(instance.CAIDs)).Error; err != nil {
+			return nil, fmt.Errorf("failed to get cas: %w", err)
+		}
+		for _, ca := range cas {
+			add(caFilePath(ca.ID), []byte(ca.Certificate))
+		}
+	}
+
+	// 站点使用的证书，多个站点可能共用同一张证书
+	if len(instance.SiteIDs) > 0 {
+		var certs []models.Cert
+		if err := a.db.WithContext(ctx).
+			Where("id IN (?)", a.db.Model(&models.Site{}).
+				Select("cert_id").
+				Where("id IN ? AND cert_id IS NOT NULL", utils.Int64Array2uint(instance.SiteIDs))).
+			Find(&certs).Error; err != nil {
+			return nil, fmt.Errorf("failed to get certs: %w", err)
+		}
+		for _, cert := range certs {
+			if !certIsIssued(&cert) { // 还没有签发的证书不下发
+				continue
+			}
+			certPathPrefix := fmt.Sprintf(constants.CertPathDir, cert.ID)
+
+			keyBytes, err := a.aesDecrypt(cert.PrivateKey)
+			if err != nil {
+				a.l.Error("failed to decrypt cert", zap.Uint("certID", cert.ID), zap.Error(err))
+				return nil, fmt.Errorf("failed to decrypt cert %d: %w", cert.ID, err)
+			}
+
+			add(certPathPrefix+constants.CertPathCertName, []byte(cert.Certificate))
+			add(certPathPrefix+constants.CertPathKeyName, keyBytes)
+			if cert.IntermediateCertificate != "" {
+				add(certPathPrefix+constants.CertPathIntermediateName, []byte(cert.IntermediateCertificate))
+			}
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+
+	return files, nil
+}
+
+// bundleWriter 写出 tar.gz 格式的文件包
+type bundleWriter struct {
+	gw      *gzip.Writer
+	tw      *tar.Writer
+	modTime time.Time
+}
+
+func newBundleWriter(w io.Writer) *bundleWriter {
+	gw := gzip.NewWriter(w)
+	return &bundleWriter{
+		gw:      gw,
+		tw:      tar.NewWriter(gw),
+		modTime: time.Now(),
+	}
+}
+
+func (b *bundleWriter) add(name string, content []byte, mode int64) error {
+	if err := b.tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    mode,
+		Size:    int64(len(content)),
+		ModTime: b.modTime,
+	}); err != nil {
+		return fmt.Errorf("failed to write header of %s: %w", name, err)
+	}
+	if _, err := b.tw.Write(content); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func (b *bundleWriter) close() error {
+	if err := b.tw.Close(); err != nil {
+		return err
+	}
+	return b.gw.Close()
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"caddy-delivery-network/app/server/certutil"
+	"caddy-delivery-network/app/server/constants"
+	"caddy-delivery-network/app/server/models"
+	"caddy-delivery-network/app/server/types"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type caSigner struct {
+	model *models.CertAuthority
+	cert  *x509.Certificate
+	key   crypto.Signer
+}
+
+// caGetSigner 读取可以用于签发的 CA
+func (a *App) caGetSigner(ctx context.Context, id uint) (*caSigner, error) {
+	var ca models.CertAuthority
+	if err := a.db.WithContext(ctx).First(&ca, "id = ?", id).Error; err != nil {
+		return nil, fmt.Errorf("failed to get ca %d: %w", id, err)
+	}
+	if ca.PrivateKey == nil {
+		return nil, fmt.Errorf("ca %d has no private key", id)
+	}
+
+	certs, err := certutil.ParseCertificatesPEM([]byte(ca.Certificate))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ca certificate: %w", err)
+	}
+	keyPEM, err := a.aesDecrypt(ca.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt ca key: %w", err)
+	}
+	key, err := certutil.ParsePrivateKeyPEM(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ca key: %w", err)
+	}
+
+	return &caSigner{model: &ca, cert: certs[0], key: key}, nil
+}
+
+// caGetChain 返回 CA 自身及其上级组成的证书链，不包含根证书
+func (a *App) caGetChain(ctx context.Context, ca *models.CertAuthority) ([][]byte, error) {
+	var chain [][]byte
+	for depth := 0; ca != nil; depth++ {
+		if depth > 8 {
+			return nil, errors.New("ca chain is too long")
+		}
+
+		certs, err := certutil.ParseCertificatesPEM([]byte(ca.Certificate))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ca %d: %w", ca.ID, err)
+		}
+		if certs[0].CheckSignatureFrom(certs[0]) == nil {
+			break // 根证书
+		}
+		chain = append(chain, certs[0].Raw)
+
+		if ca.ParentID == nil {
+			break
+		}
+		var parent models.CertAuthority
+		if err := a.db.WithContext(ctx).First(&parent, "id = ?", *ca.ParentID).Error; err != nil {
+			return nil, fmt.Errorf("failed to get ca %d: %w", *ca.ParentID, err)
+		}
+		ca = &parent
+	}
+
+	return chain, nil
+}
+
+// caCheckAbleToDelete 检查是否还有下级 CA 、自动签发的证书或实例在使用这个 CA
+func (a *App) caCheckAbleToDelete(ctx context.Context, id uint) (bool, error) {
+	var count int64
+	if err := a.db.WithContext(ctx).
+		Model(&models.CertAuthority{}).
+		Where("parent_id = ?", id).
+		Count(&count).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		a.l.Error("failed to count child cas", zap.Error(err))
+		return false, fmt.Errorf("failed to count child cas: %w", err)
+	} else if count > 0 {
+		return false, nil
+	}
+
+	if err := a.db.WithContext(ctx).
+		Model(&models.Cert{}).
+		Where("provider->>'type' = ? AND (provider->>'ca_id')::bigint = ?", types.CertProviderInternalCA, id).
+		Count(&count).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		a.l.Error("failed to count certs", zap.Error(err))
+		return false, fmt.Errorf("failed to count certs: %w", err)
+	} else if count > 0 {
+		return false, nil
+	}
+
+	if err := a.db.WithContext(ctx).
+		Model(&models.Instance{}).
+		Where("? = ANY(ca_ids)", id).
+		Count(&count).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		a.l.Error("failed to count instances", zap.Error(err))
+		return false, fmt.Errorf("failed to count instances: %w", err)
+	}
+
+	return count == 0, nil
+}
+
+// caFilePath 实例上 CA 证书的存放位置
+func caFilePath(id uint) string {
+	return fmt.Sprintf(constants.CAPathFile, id)
+}
@@ -0,0 +1,401 @@
+package handlers
+
+import (
+	"caddy-delivery-network/app/server/acme"
+	"caddy-delivery-network/app/server/certutil"
+	"caddy-delivery-network/app/server/constants"
+	"caddy-delivery-network/app/server/dnsprovider"
+	"caddy-delivery-network/app/server/models"
+	"caddy-delivery-network/app/server/types"
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+func (a *App) certParseProvider(raw json.RawMessage) (*types.CertProvider, error) {
+	if raw == nil {
+		return nil, errors.New("cert is in manual mode")
+	}
+
+	var provider types.CertProvider
+	if err := json.Unmarshal(raw, &provider); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal provider: %w", err)
+	}
+
+	return &provider, nil
+}
+
+// certValidateProvider 检查提供方配置，避免无效的配置直到后台续期时才发现
+func (a *App) certValidateProvider(ctx context.Context, raw json.RawMessage) (error, int) {
+	provider, err := a.certParseProvider(raw)
+	if err != nil {
+		return err, http.StatusBadRequest
+	}
+
+	switch certutil.KeyType(provider.KeyType) {
+	case "", certutil.KeyTypeEC256, certutil.KeyTypeEC384, certutil.KeyTypeRSA2048, certutil.KeyTypeRSA4096:
+	default:
+		return fmt.Errorf("unsupported key type %q", provider.KeyType), http.StatusBadRequest
+	}
+
+	switch provider.Type {
+	case types.CertProviderACME:
+		if u, err := url.Parse(provider.DirectoryURL); err != nil || !u.IsAbs() || u.Host == "" {
+			return fmt.Errorf("invalid directory url %q", provider.DirectoryURL), http.StatusBadRequest
+		}
+		if provider.EAB != nil {
+			if provider.EAB.KID == "" {
+				return errors.New("eab kid is empty"), http.StatusBadRequest
+			}
+			if _, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(provider.EAB.HMACKey, "=")); err != nil || provider.EAB.HMACKey == "" {
+				return errors.New("eab hmac key should be base64url encoded"), http.StatusBadRequest
+			}
+		}
+		if provider.TrustedRoots != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(provider.TrustedRoots)) {
+			return errors.New("failed to parse trusted roots"), http.StatusBadRequest
+		}
+		switch provider.Challenge {
+		case "":
+		case acme.ChallengeTypeHTTP01:
+			if a.publicEndpoint == "" {
+				return errors.New("http-01 challenge requires the server public endpoint to be configured"), http.StatusBadRequest
+			}
+		case acme.ChallengeTypeDNS01:
+			if provider.DNS == nil {
+				return errors.New("dns-01 challenge requires a dns provider"), http.StatusBadRequest
+			}
+		default:
+			return fmt.Errorf("unsupported challenge type %q", provider.Challenge), http.StatusBadRequest
+		}
+		if provider.DNS != nil {
+			if _, err := dnsprovider.New(provider.DNS); err != nil {
+				return fmt.Errorf("invalid dns provider: %w", err), http.StatusBadRequest
+			}
+		}
+		if provider.DNS == nil && a.publicEndpoint == "" {
+			return errors.New("no challenge solver available"), http.StatusBadRequest
+		}
+	case types.CertProviderInternalCA:
+		if provider.Validity != "" {
+			if validity, err := time.ParseDuration(provider.Validity); err != nil || validity <= 0 {
+				return fmt.Errorf("invalid validity %q", provider.Validity), http.StatusBadRequest
+			}
+		}
+		var counter int64
+		if err := a.db.WithContext(ctx).Model(&models.CertAuthority{}).Where("id = ?", provider.CAID).Count(&counter).Error; err != nil {
+			return fmt.Errorf("failed to get ca: %w", err), http.StatusInternalServerError
+		} else if counter == 0 {
+			return fmt.Errorf("ca %d not found", provider.CAID), http.StatusBadRequest
+		}
+	default:
+		return fmt.Errorf("unsupported provider type %q", provider.Type), http.StatusBadRequest
+	}
+
+	return nil, http.StatusOK
+}
+
+func (a *App) acmeGetClient(ctx context.Context, provider *types.CertProvider) (*acme.Client, error) {
+	cfg := &acme.Config{
+		DirectoryURL: provider.DirectoryURL,
+		Email:        provider.Email,
+		TrustedRoots: provider.TrustedRoots,
+		Logger:       a.l.Named("acme"),
+	}
+	if provider.EAB != nil {
+		cfg.EAB = &acme.EAB{
+			KID:     provider.EAB.KID,
+			HMACKey: provider.EAB.HMACKey,
+		}
+	}
+
+	// 查找已有的账户
+	var account models.AcmeAccount
+	if err := a.db.WithContext(ctx).
+		First(&account, "directory_url = ? AND email = ?", provider.DirectoryURL, provider.Email).
+		Error; err == nil {
+		keyPEM, err := a.aesDecrypt(account.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt account key: %w", err)
+		}
+		key, err := certutil.ParsePrivateKeyPEM(keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse account key: %w", err)
+		}
+
+		return acme.New(cfg, key, account.URI)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get acme account: %w", err)
+	}
+
+	// 没有账户，需要注册一个
+	key, err := certutil.GenerateKey(certutil.KeyTypeEC256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate account key: %w", err)
+	}
+	client, err := acme.New(cfg, key, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create acme client: %w", err)
+	}
+	uri, err := client.Register(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register acme account: %w", err)
+	}
+
+	// 保存账户
+	keyPEM, err := certutil.EncodePrivateKeyPEM(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode account key: %w", err)
+	}
+	encryptedKey, err := a.aesEncrypt(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt account key: %w", err)
+	}
+	account = models.AcmeAccount{
+		DirectoryURL: provider.DirectoryURL,
+		Email:        provider.Email,
+		URI:          uri,
+		PrivateKey:   encryptedKey,
+	}
+	if err = a.db.WithContext(ctx).Create(&account).Error; err != nil {
+		return nil, fmt.Errorf("failed to save acme account: %w", err)
+	}
+
+	a.l.Info("acme account registered", zap.String("directory", provider.DirectoryURL), zap.String("uri", uri))
+
+	return client, nil
+}
+
+// certUsesHTTP01 证书由 server 通过 ACME 签发并且可能使用 HTTP-01 验证（需要实例把验证请求转发给 server ）
+func (a *App) certUsesHTTP01(cert *models.Cert) bool {
+	if cert.Provider == nil || a.publicEndpoint == "" {
+		return false
+	}
+	provider, err := a.certParseProvider(cert.Provider)
+	if err != nil {
+		a.l.Error("failed to parse cert provider", zap.Uint("certID", cert.ID), zap.Error(err))
+		return false
+	}
+	return provider.Type == types.CertProviderACME && provider.Challenge != acme.ChallengeTypeDNS01
+}
+
+func (a *App) acmeGetSolvers(provider *types.CertProvider) ([]acme.Solver, error) {
+	var solvers []acme.Solver
+
+	// 配置了 DNS 提供方时才能使用 DNS-01
+	if provider.DNS != nil {
+		dns, err := dnsprovider.New(provider.DNS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create dns provider: %w", err)
+		}
+		solvers = append(solvers, acme.NewDNS01Solver(dns))
+	}
+
+	// 只指定了 DNS-01 时不再尝试 HTTP-01 ；没有配置 server 对外地址时实例无法转发验证请求，也不能使用 HTTP-01
+	if provider.Challenge != acme.ChallengeTypeDNS01 && a.publicEndpoint != "" {
+		http01 := &acmeHTTP01Solver{rdb: a.rdb}
+		if provider.Challenge == acme.ChallengeTypeHTTP01 {
+			solvers = append([]acme.Solver{http01}, solvers...)
+		} else {
+			solvers = append(solvers, http01)
+		}
+	}
+
+	if len(solvers) == 0 {
+		return nil, errors.New("no challenge solver available")
+	}
+
+	return solvers, nil
+}
+
+func (a *App) certIssueACME(ctx context.Context, cert *models.Cert, provider *types.CertProvider) error {
+	if provider.Challenge == acme.ChallengeTypeHTTP01 && a.publicEndpoint == "" {
+		return errors.New("http-01 challenge requires the server public endpoint to be configured")
+	}
+
+	client, err := a.acmeGetClient(ctx, provider)
+	if err != nil {
+		return err
+	}
+
+	solvers, err := a.acmeGetSolvers(provider)
+	if err != nil {
+		return err
+	}
+
+	// 每次签发都使用新的私钥
+	key, err := certutil.GenerateKey(certutil.KeyType(provider.KeyType))
+	if err != nil {
+		return fmt.Errorf("failed to generate cert key: %w", err)
+	}
+
+	issued, err := client.Obtain(ctx, cert.Domains, key, solvers)
+	if err != nil {
+		return fmt.Errorf("failed to obtain cert: %w", err)
+	}
+
+	// 写入证书信息
+	keyPEM, err := certutil.EncodePrivateKeyPEM(key)
+	if err != nil {
+		return fmt.Errorf("failed to encode cert key: %w", err)
+	}
+	encryptedKey, err := a.aesEncrypt(keyPEM)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt cert key: %w", err)
+	}
+
+	cert.Certificate = certutil.EncodeCertificatesPEM(issued.Leaf.Raw)
+	cert.IntermediateCertificate = certutil.EncodeCertificatesPEM(issued.Chain...)
+	cert.PrivateKey = encryptedKey
+	cert.CSR = certutil.EncodeCSRPEM(issued.CSR)
+	cert.ExpiresAt = issued.Leaf.NotAfter
+	cert.Domains = issued.Leaf.DNSNames
+
+	return nil
+}
+
+func (a *App) certIssueInternalCA(ctx context.Context, cert *models.Cert, provider *types.CertProvider) error {
+	validity := constants.CertInternalCAValidity
+	if provider.Validity != "" {
+		var err error
+		if validity, err = time.ParseDuration(provider.Validity); err != nil || validity <= 0 {
+			return fmt.Errorf("invalid validity %q", provider.Validity)
+		}
+	}
+
+	signer, err := a.caGetSigner(ctx, provider.CAID)
+	if err != nil {
+		return err
+	}
+	chain, err := a.caGetChain(ctx, signer.model)
+	if err != nil {
+		return err
+	}
+
+	// 每次签发都使用新的私钥
+	key, err := certutil.GenerateKey(certutil.KeyType(provider.KeyType))
+	if err != nil {
+		return fmt.Errorf("failed to generate cert key: %w", err)
+	}
+
+	der, err := certutil.IssueLeaf(cert.Domains, key, validity, signer.cert, signer.key)
+	if err != nil {
+		return fmt.Errorf("failed to issue cert: %w", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return fmt.Errorf("failed to parse issued cert: %w", err)
+	}
+
+	// 写入证书信息
+	keyPEM, err := certutil.EncodePrivateKeyPEM(key)
+	if err != nil {
+		return fmt.Errorf("failed to encode cert key: %w", err)
+	}
+	encryptedKey, err := a.aesEncrypt(keyPEM)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt cert key: %w", err)
+	}
+
+	cert.Certificate = certutil.EncodeCertificatesPEM(der)
+	cert.IntermediateCertificate = certutil.EncodeCertificatesPEM(chain...)
+	cert.PrivateKey = encryptedKey
+	cert.CSR = ""
+	cert.ExpiresAt = leaf.NotAfter
+
+	return nil
+}
+
+func (a *App) certIssue(ctx context.Context, cert *models.Cert) error {
+	provider, err := a.certParseProvider(cert.Provider)
+	if err != nil {
+		return err
+	}
+
+	switch provider.Type {
+	case types.CertProviderACME:
+		return a.certIssueACME(ctx, cert, provider)
+	case types.CertProviderInternalCA:
+		return a.certIssueInternalCA(ctx, cert, provider)
+	default:
+		return fmt.Errorf("unsupported provider type %q", provider.Type)
+	}
+}
+
+// certIsIssued 证书与私钥都已经存在（只生成了 CSR 或者签发失败的证书还不能部署）
+func certIsIssued(cert *models.Cert) bool {
+	return cert.Certificate != "" && len(cert.PrivateKey) > 0
+}
+
+// certRenewByModel 调用 provider 重新签发证书，保存并通知部署了该证书的实例
+func (a *App) certRenewByModel(ctx context.Context, cert *models.Cert) error {
+	issueCtx, cancel := context.WithTimeout(ctx, constants.CertIssueTimeout)
+	defer cancel()
+
+	previous := *cert
+	hadIntermediate := cert.IntermediateCertificate != ""
+	if err := a.certIssue(issueCtx, cert); err != nil {
+		a.l.Error("failed to issue cert", zap.Uint("id", cert.ID), zap.Error(err))
+		return fmt.Errorf("failed to issue cert: %w", err)
+	}
+
+	// 中间证书可能变为空，所以需要用 Select 强制更新；在同一个事务中记录版本
+	if err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := a.certSnapshotVersion(tx, &previous); err != nil {
+			return err
+		}
+		if err := tx.
+			Model(cert).
+			Select("certificate", "intermediate_certificate", "private_key", "csr", "expires_at", "domains").
+			Updates(cert).Error; err != nil {
+			return fmt.Errorf("failed to update cert: %w", err)
+		}
+		return a.certSaveVersion(tx, cert, constants.CertVersionSourceRenew)
+	}); err != nil {
+		a.l.Error("failed to update cert", zap.Uint("id", cert.ID), zap.Error(err))
+		return fmt.Errorf("failed to update cert: %w", err)
+	}
+
+	// 清理缓存
+	if err := a.certUpdateClearCache(ctx, cert.ID, hadIntermediate != (cert.IntermediateCertificate != "")); err != nil {
+		a.l.Error("failed to clear cache", zap.Error(err))
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+
+	// 站点的域名可能在签发之后被修改过
+	a.certLogCoverage(ctx, cert)
+
+	return nil
+}
+
+// certRenewWithRecord 续期并记录本次尝试的结果
+func (a *App) certRenewWithRecord(ctx context.Context, cert *models.Cert, trigger string) error {
+	record := models.CertRenewRecord{
+		CertID:    cert.ID,
+		Trigger:   trigger,
+		StartedAt: time.Now(),
+	}
+
+	renewErr := a.certRenewByModel(ctx, cert)
+
+	record.FinishedAt = time.Now()
+	record.Success = renewErr == nil
+	if renewErr != nil {
+		record.Error = renewErr.Error()
+	}
+
+	// 即使请求已经被取消也要记录结果
+	if err := a.db.WithContext(context.WithoutCancel(ctx)).Create(&record).Error; err != nil {
+		a.l.Error("failed to save cert renew record", zap.Any("record", record), zap.Error(err))
+	}
+
+	return renewErr
+}
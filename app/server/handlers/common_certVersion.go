@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"caddy-delivery-network/app/server/certutil"
+	"caddy-delivery-network/app/server/constants"
+	"caddy-delivery-network/app/server/models"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// certLockVersion 锁住证书行直到事务结束，让同一张证书的版本号分配串行进行
+func (a *App) certLockVersion(tx *gorm.DB, certID uint) error {
+	if err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&models.Cert{}, "id = ?", certID).Error; err != nil {
+		return fmt.Errorf("failed to lock cert: %w", err)
+	}
+	return nil
+}
+
+// certSnapshotVersion 在覆盖证书内容之前调用：如果当前内容还没有对应的版本（例如引入版本记录之前就已经存在的证书），先把它记录下来
+func (a *App) certSnapshotVersion(tx *gorm.DB, cert *models.Cert) error {
+	if cert.Certificate == "" {
+		return nil
+	}
+	if err := a.certLockVersion(tx, cert.ID); err != nil {
+		return err
+	}
+
+	var count int64
+	if err := tx.
+		Model(&models.CertVersion{}).
+		Where("cert_id = ? AND certificate = ?", cert.ID, cert.Certificate).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check existing version: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	return a.certSaveVersion(tx, cert, constants.CertVersionSourceSnapshot)
+}
+
+// certSaveVersion 把证书当前的内容记录为一个新版本，还没有证书内容（例如只生成了 CSR ）时跳过
+// 需要与证书内容的更新在同一个事务中调用，避免内容与版本记录不一致
+func (a *App) certSaveVersion(tx *gorm.DB, cert *models.Cert, source string) error {
+	if cert.Certificate == "" {
+		return nil
+	}
+
+	version := models.CertVersion{
+		CertID:                  cert.ID,
+		Source:                  source,
+		Domains:                 cert.Domains,
+		Certificate:             cert.Certificate,
+		PrivateKey:              cert.PrivateKey,
+		IntermediateCertificate: cert.IntermediateCertificate,
+		CSR:                     cert.CSR,
+	}
+	if certs, err := certutil.ParseCertificatesPEM([]byte(cert.Certificate)); err == nil {
+		version.Issuer = certs[0].Issuer.String()
+		version.Serial = certs[0].SerialNumber.Text(16)
+		version.NotBefore = certs[0].NotBefore
+		version.NotAfter = certs[0].NotAfter
+	}
+
+	if err := a.certLockVersion(tx, cert.ID); err != nil {
+		return err
+	}
+	var latest uint
+	if err := tx.
+		Unscoped().
+		Model(&models.CertVersion{}).
+		Where("cert_id = ?", cert.ID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error; err != nil {
+		return fmt.Errorf("failed to get latest version: %w", err)
+	}
+	version.Version = latest + 1
+
+	if err := tx.Create(&version).Error; err != nil {
+		return fmt.Errorf("failed to save version: %w", err)
+	}
+
+	return nil
+}
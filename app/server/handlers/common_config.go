@@ -23,24 +23,31 @@ func (a *App) buildInstanceConfigByID(ctx context.Context, instanceID uint) (str
 }
 
 func (a *App) buildInstanceConfigByModel(ctx context.Context, instance *models.Instance) (string, error) {
-	// 添加 preconfig 内容
-	configSections := []string{instance.PreConfig}
+	// 添加 preconfig 内容，每一段前面都加上标记，方便实例定位出错的站点
+	configSections := []string{constants.ConfigMarkerPreConfig + "\n" + instance.PreConfig}
+
+	// 实例上作为信任根的 CA 证书，以空格分隔，站点模板可以通过 {{.TrustedCAs}} 引用（例如 tls_trust_pool file {{.TrustedCAs}} ）
+	var caPaths []string
+	for _, caID := range instance.CAIDs {
+		caPaths = append(caPaths, dataRootPath(caFilePath(uint(caID))))
+	}
+	trustedCAs := strings.Join(caPaths, " ")
 
 	// 依次添加站点
 	for _, siteID := range instance.SiteIDs {
-		siteConfig, err := a.buildSiteConfigByID(ctx, uint(siteID))
+		siteConfig, err := a.buildSiteConfigByID(ctx, uint(siteID), trustedCAs)
 		if err != nil {
 			a.l.Error("failed to build site config", zap.Uint("siteID", uint(siteID)), zap.Error(err))
 			return "", fmt.Errorf("failed to build site config %d: %w", siteID, err)
 		}
-		configSections = append(configSections, siteConfig)
+		configSections = append(configSections, fmt.Sprintf(constants.ConfigMarkerSite, siteID)+"\n"+siteConfig)
 	}
 
 	// 连接所有内容
 	return strings.Join(configSections, "\n\n"), nil
 }
 
-func (a *App) buildSiteConfigByID(ctx context.Context, siteID uint) (string, error) {
+func (a *App) buildSiteConfigByID(ctx context.Context, siteID uint, trustedCAs string) (string, error) {
 	// 寻找 site
 	var site models.Site
 	if err := a.db.WithContext(ctx).
@@ -52,10 +59,10 @@ func (a *App) buildSiteConfigByID(ctx context.Context, siteID uint) (string, err
 		return "", fmt.Errorf("failed to find site with id %d: %w", siteID, err)
 	}
 
-	return a.buildSiteConfigByModel(&site)
+	return a.buildSiteConfigByModel(&site, trustedCAs)
 }
 
-func (a *App) buildSiteConfigByModel(site *models.Site) (string, error) {
+func (a *App) buildSiteConfigByModel(site *models.Site, trustedCAs string) (string, error) {
 	// 准备模板
 	siteTemplate, err := template.New(fmt.Sprintf("site-%d", site.ID)).Parse(site.Template.Content)
 	if err != nil {
@@ -66,29 +73,45 @@ func (a *App) buildSiteConfigByModel(site *models.Site) (string, error) {
 	// 准备数据
 	data := make(map[string]string)
 
-	// 添加保留字段
+	// 添加保留字段（没有内容时也需要设置为空，否则模板会输出 <no value> ）
 	data["Origin"] = site.Origin
-	if site.Cert != nil {
-		certPathPrefix := fmt.Sprintf(constants.CertPathDir, site.CertID)
+	data["TrustedCAs"] = trustedCAs
+	data["Cert"] = ""
+	data["ACMEChallenge"] = ""
+	if site.Cert != nil && certIsIssued(site.Cert) { // 还没有签发的证书没有文件，让 Caddy 按默认方式处理
+		certPathPrefix := fmt.Sprintf(constants.CertPathDir, site.Cert.ID)
 
 		// 添加基础信息
 		tlsConfig := fmt.Sprintf(
 			"tls %s %s",
-			certPathPrefix+constants.CertPathCertName,
-			certPathPrefix+constants.CertPathKeyName,
+			dataRootPath(certPathPrefix+constants.CertPathCertName),
+			dataRootPath(certPathPrefix+constants.CertPathKeyName),
 		)
 
 		// 添加中间证书
 		if site.Cert.IntermediateCertificate != "" {
 			tlsConfig += fmt.Sprintf(
 				" {\n        ca_root %s\n    }",
-				certPathPrefix+constants.CertPathIntermediateName,
+				dataRootPath(certPathPrefix+constants.CertPathIntermediateName),
 			)
 		}
 
 		data["Cert"] = tlsConfig
 	}
 
+	// 证书由 server 通过 ACME 签发时，把 HTTP-01 验证请求转发给 server ，这样无论 CA 访问到哪个实例都能通过验证；
+	// 证书还没有签发时同样需要，首次签发依赖于此
+	if site.Cert != nil && a.certUsesHTTP01(site.Cert) {
+		data["ACMEChallenge"] = fmt.Sprintf(
+			"handle /.well-known/acme-challenge/* {\n        reverse_proxy %s {\n            header_up Host {upstream_hostport}\n        }\n    }",
+			a.publicEndpoint,
+		)
+		if !strings.Contains(site.Template.Content, ".ACMEChallenge") {
+			a.l.Warn("site template does not reference {{.ACMEChallenge}}, http-01 challenges will not reach the server",
+				zap.Uint("siteID", site.ID), zap.Uint("templateID", site.Template.ID))
+		}
+	}
+
 	// 添加自定义字段
 	for index, fieldName := range site.Template.Variables {
 		data[fieldName] = site.TemplateValues[index]
@@ -104,3 +127,8 @@ func (a *App) buildSiteConfigByModel(site *models.Site) (string, error) {
 	// 成功返回
 	return buf.String(), nil
 }
+
+// dataRootPath 配置中引用实例上文件的路径，由实例把占位符替换为自己的数据根目录
+func dataRootPath(relPath string) string {
+	return constants.DataRootPlaceholder + "/" + relPath
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+// 只有持有者才能释放锁，避免锁过期后误删其他副本的锁
+var lockReleaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// 只有持有者才能续期
+var lockExtendScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// lockAcquire 尝试获取分布式锁，成功时返回用于释放的 token
+func (a *App) lockAcquire(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	token := uuid.NewString()
+	ok, err := a.rdb.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to acquire lock %s: %w", key, err)
+	}
+
+	return token, ok, nil
+}
+
+// lockExtend 延长持有的锁的有效期，锁已经过期或被其他副本持有时返回 false
+func (a *App) lockExtend(ctx context.Context, key string, token string, ttl time.Duration) (bool, error) {
+	res, err := lockExtendScript.Run(ctx, a.rdb, []string{key}, token, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to extend lock %s: %w", key, err)
+	}
+
+	return res == 1, nil
+}
+
+func (a *App) lockRelease(ctx context.Context, key string, token string) error {
+	if err := lockReleaseScript.Run(ctx, a.rdb, []string{key}, token).Err(); err != nil {
+		return fmt.Errorf("failed to release lock %s: %w", key, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"caddy-delivery-network/app/server/constants"
+	"context"
+	"go.uber.org/zap"
+	"strconv"
+	"sync"
+)
+
+// instanceNotifier 记录连接在这个副本上的实例事件流
+type instanceNotifier struct {
+	mu   sync.Mutex
+	subs map[uint]map[chan struct{}]struct{}
+}
+
+// instanceNotify 通知实例数据发生了变化，通过 Redis 发布给所有副本。
+// 调用方需要在数据写入数据库之后再清理缓存并通知，否则实例可能在写入之前重新拉取，把旧数据重新写进缓存
+func (a *App) instanceNotify(ctx context.Context, id uint) {
+	if err := a.rdb.Publish(ctx, constants.PubSubChannelInstanceNotify, strconv.FormatUint(uint64(id), 10)).Err(); err != nil {
+		// 实例还会按照心跳间隔轮询，通知失败只会延迟生效
+		a.l.Error("failed to publish instance notify", zap.Uint("id", id), zap.Error(err))
+	}
+}
+
+// instanceSubscribe 注册一个事件流，返回的 channel 在数据变化时收到信号
+func (a *App) instanceSubscribe(id uint) (chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	a.notifier.mu.Lock()
+	if a.notifier.subs[id] == nil {
+		a.notifier.subs[id] = make(map[chan struct{}]struct{})
+	}
+	a.notifier.subs[id][ch] = struct{}{}
+	a.notifier.mu.Unlock()
+
+	return ch, func() {
+		a.notifier.mu.Lock()
+		delete(a.notifier.subs[id], ch)
+		if len(a.notifier.subs[id]) == 0 {
+			delete(a.notifier.subs, id)
+		}
+		a.notifier.mu.Unlock()
+	}
+}
+
+func (a *App) instanceDispatch(id uint) {
+	a.notifier.mu.Lock()
+	defer a.notifier.mu.Unlock()
+
+	for ch := range a.notifier.subs[id] {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// 已经有未处理的信号，合并
+		}
+	}
+}
+
+// StartInstanceNotifyListener 订阅 Redis 通知频道，转发给连接在这个副本上的实例
+func (a *App) StartInstanceNotifyListener(ctx context.Context) {
+	go func() {
+		pubsub := a.rdb.Subscribe(ctx, constants.PubSubChannelInstanceNotify)
+		defer pubsub.Close()
+
+		// go-redis 会在断线后自动重新订阅
+		ch := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				a.l.Debug("stop instance notify listener")
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				id, err := strconv.ParseUint(msg.Payload, 10, 64)
+				if err != nil {
+					a.l.Error("invalid instance notify payload", zap.String("payload", msg.Payload), zap.Error(err))
+					continue
+				}
+				a.instanceDispatch(uint(id))
+			}
+		}
+	}()
+}
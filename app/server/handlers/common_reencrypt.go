@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"caddy-delivery-network/app/server/constants"
+	"caddy-delivery-network/app/server/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"time"
+)
+
+// reencryptTargets 所有加密存储了私钥的表，私钥都在 private_key 列中
+var reencryptTargets = []any{
+	&models.Cert{},
+	&models.AcmeAccount{},
+	&models.CertAuthority{},
+	&models.CertVersion{},
+}
+
+type reencryptStatus struct {
+	State      string    `json:"state"`
+	PrimaryKey string    `json:"primary_key"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Total      int64     `json:"total"`    // 检查过的记录数
+	Migrated   int64     `json:"migrated"` // 迁移到主密钥的记录数
+	Failed     int64     `json:"failed"`   // 无法解密的记录数
+	Error      string    `json:"error"`
+}
+
+func (a *App) reencryptGetStatus(ctx context.Context) (*reencryptStatus, error) {
+	statusBytes, err := a.rdb.Get(ctx, constants.CacheKeyReencryptStatus).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return &reencryptStatus{State: constants.ReencryptStateIdle, PrimaryKey: a.kr.Primary()}, nil
+		}
+		return nil, fmt.Errorf("failed to get reencrypt status: %w", err)
+	}
+
+	var status reencryptStatus
+	if err = json.Unmarshal(statusBytes, &status); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal reencrypt status: %w", err)
+	}
+
+	// 任务运行期间会一直持有锁，锁已经不存在说明运行任务的进程中途退出了
+	if status.State == constants.ReencryptStateRunning {
+		exists, err := a.rdb.Exists(ctx, constants.LockKeyReencrypt).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to check reencrypt lock: %w", err)
+		}
+		if exists == 0 {
+			status.State = constants.ReencryptStateFailed
+			status.Error = "task was interrupted"
+		}
+	}
+
+	return &status, nil
+}
+
+func (a *App) reencryptSaveStatus(ctx context.Context, status *reencryptStatus) {
+	statusBytes, err := json.Marshal(status)
+	if err != nil {
+		a.l.Error("failed to marshal reencrypt status", zap.Error(err))
+		return
+	}
+	if err = a.rdb.Set(ctx, constants.CacheKeyReencryptStatus, statusBytes, 0).Err(); err != nil {
+		a.l.Error("failed to save reencrypt status", zap.Error(err))
+	}
+}
+
+// reencryptStart 获取任务锁并在后台运行重新加密任务，已有任务在运行时返回 false
+func (a *App) reencryptStart(ctx context.Context) (*reencryptStatus, bool, error) {
+	token, ok, err := a.lockAcquire(ctx, constants.LockKeyReencrypt, constants.ReencryptLockTTL)
+	if err != nil {
+		return nil, false, err
+	} else if !ok {
+		return nil, false, nil
+	}
+
+	status := &reencryptStatus{
+		State:      constants.ReencryptStateRunning,
+		PrimaryKey: a.kr.Primary(),
+		StartedAt:  time.Now(),
+	}
+	a.reencryptSaveStatus(ctx, status)
+	started := *status
+
+	// 任务与请求无关，请求结束后继续运行
+	lockCtx := context.WithoutCancel(ctx)
+	jobCtx, cancel := context.WithCancel(lockCtx)
+	go func() {
+		defer func() {
+			if err := a.lockRelease(lockCtx, constants.LockKeyReencrypt, token); err != nil {
+				a.l.Error("failed to release reencrypt lock", zap.Error(err))
+			}
+		}()
+
+		defer cancel()
+
+		done := make(chan struct{})
+		go a.reencryptKeepLock(lockCtx, cancel, token, done)
+		defer close(done)
+
+		a.reencryptRun(jobCtx, status)
+	}()
+
+	return &started, true, nil
+}
+
+// reencryptKeepLock 在任务运行期间定期续期任务锁，锁丢失时取消任务，避免与新开始的任务同时运行；任务结束时关闭 done
+func (a *App) reencryptKeepLock(ctx context.Context, cancel context.CancelFunc, token string, done <-chan struct{}) {
+	ticker := time.NewTicker(constants.ReencryptLockRefreshTime)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		ok, err := a.lockExtend(ctx, constants.LockKeyReencrypt, token, constants.ReencryptLockTTL)
+		if err != nil {
+			// 暂时联系不上 redis ，锁还没有过期，下一轮再试
+			a.l.Error("failed to extend reencrypt lock", zap.Error(err))
+			continue
+		}
+		if !ok {
+			a.l.Error("reencrypt lock lost, stopping task")
+			cancel()
+			return
+		}
+	}
+}
+
+func (a *App) reencryptRun(ctx context.Context, status *reencryptStatus) {
+	a.l.Info("reencrypt started", zap.String("primary", status.PrimaryKey))
+
+	for _, target := range reencryptTargets {
+		if err := a.reencryptTable(ctx, target, status); err != nil {
+			if ctx.Err() != nil {
+				// 锁已经丢失，可能已经有新的任务在运行，不再覆盖它的状态
+				a.l.Error("reencrypt cancelled", zap.Error(err))
+				return
+			}
+			a.l.Error("reencrypt failed", zap.Error(err))
+			status.State = constants.ReencryptStateFailed
+			status.Error = err.Error()
+			status.FinishedAt = time.Now()
+			a.reencryptSaveStatus(ctx, status)
+			return
+		}
+	}
+
+	// 有无法解密的记录时还不能停用旧密钥
+	status.State = constants.ReencryptStateDone
+	if status.Failed > 0 {
+		status.State = constants.ReencryptStateFailed
+		status.Error = fmt.Sprintf("%d records could not be decrypted", status.Failed)
+	}
+	status.FinishedAt = time.Now()
+	a.reencryptSaveStatus(ctx, status)
+
+	a.l.Info("reencrypt finished", zap.Any("status", status))
+}
+
+func (a *App) reencryptTable(ctx context.Context, model any, status *reencryptStatus) error {
+	var lastID uint
+	for {
+		// 包括已经软删除的记录，避免恢复后无法解密
+		var rows []struct {
+			ID         uint
+			PrivateKey []byte
+		}
+		if err := a.db.WithContext(ctx).
+			Unscoped().
+			Model(model).
+			Select("id", "private_key").
+			Where("id > ? AND private_key IS NOT NULL", lastID).
+			Order("id ASC").
+			Limit(constants.ReencryptBatchSize).
+			Find(&rows).Error; err != nil {
+			return fmt.Errorf("failed to get records: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		for _, row := range rows {
+			lastID = row.ID
+			status.Total++
+
+			if a.kr.KeyID(row.PrivateKey) == status.PrimaryKey {
+				continue
+			}
+
+			plaintext, err := a.aesDecrypt(row.PrivateKey)
+			if err != nil {
+				a.l.Error("failed to decrypt record", zap.String("model", fmt.Sprintf("%T", model)), zap.Uint("id", row.ID), zap.Error(err))
+				status.Failed++
+				continue
+			}
+			encrypted, err := a.aesEncrypt(plaintext)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt record %d: %w", row.ID, err)
+			}
+
+			// 只在内容没有被同时修改时更新；不更新 updated_at ，避免实例重新拉取没有变化的文件
+			result := a.db.WithContext(ctx).
+				Unscoped().
+				Model(model).
+				Where("id = ? AND private_key = ?", row.ID, row.PrivateKey).
+				UpdateColumn("private_key", encrypted)
+			if result.Error != nil {
+				return fmt.Errorf("failed to update record %d: %w", row.ID, result.Error)
+			}
+			if result.RowsAffected > 0 {
+				status.Migrated++
+			}
+		}
+
+		a.reencryptSaveStatus(ctx, status)
+	}
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"caddy-delivery-network/app/server/certutil"
+	"caddy-delivery-network/app/server/gen/oapi/admin"
+	"caddy-delivery-network/app/server/models"
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"net"
+	"strings"
+	"unicode"
+)
+
+const (
+	siteCoverageWarning = "warning" // 部分主机名没有被覆盖
+	siteCoverageError   = "error"   // 所有主机名都没有被覆盖，基本可以确定是选错了证书
+)
+
+type siteCoverageIssue struct {
+	SiteID   uint
+	SiteName string
+	CertID   uint
+	Hostname string
+	Severity string
+	Message  string
+}
+
+// siteParseHostnames 从 Caddyfile 的站点地址中提取需要证书的主机名，跳过 http:// 、占位符和没有主机名的地址
+func siteParseHostnames(origin string) []string {
+	var (
+		hosts []string
+		seen  = make(map[string]bool)
+	)
+	for _, addr := range strings.FieldsFunc(origin, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		if strings.Contains(addr, "{") {
+			continue
+		}
+
+		if scheme, rest, found := strings.Cut(addr, "://"); found {
+			if strings.EqualFold(scheme, "http") {
+				continue
+			}
+			addr = rest
+		}
+		if i := strings.IndexByte(addr, '/'); i >= 0 {
+			addr = addr[:i]
+		}
+
+		host := addr
+		if h, port, err := net.SplitHostPort(addr); err == nil {
+			if port == "80" {
+				continue
+			}
+			host = h
+		}
+		host = strings.Trim(strings.TrimSuffix(strings.ToLower(host), "."), "[]")
+
+		if host != "" && !seen[host] {
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+
+	return hosts
+}
+
+// siteCheckCoverage 检查站点的主机名是否都被证书覆盖， domains 为证书（即将使用）的域名
+func siteCheckCoverage(site *models.Site, certID uint, domains []string) []siteCoverageIssue {
+	hosts := siteParseHostnames(site.Origin)
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	if len(domains) == 0 {
+		// 自动签发还没有完成，或者只生成了 CSR
+		return []siteCoverageIssue{{
+			SiteID:   site.ID,
+			SiteName: site.Name,
+			CertID:   certID,
+			Severity: siteCoverageWarning,
+			Message:  "cert has no domains yet",
+		}}
+	}
+
+	var uncovered []string
+	for _, host := range hosts {
+		if !certutil.MatchDomain(domains, host) {
+			uncovered = append(uncovered, host)
+		}
+	}
+
+	severity := siteCoverageWarning
+	if len(uncovered) == len(hosts) {
+		severity = siteCoverageError
+	}
+
+	var issues []siteCoverageIssue
+	for _, host := range uncovered {
+		issues = append(issues, siteCoverageIssue{
+			SiteID:   site.ID,
+			SiteName: site.Name,
+			CertID:   certID,
+			Hostname: host,
+			Severity: severity,
+			Message:  fmt.Sprintf("%s is not covered by cert domains %s", host, strings.Join(domains, ", ")),
+		})
+	}
+
+	return issues
+}
+
+// siteCheckCoverageByModel 检查站点与它当前的证书
+func (a *App) siteCheckCoverageByModel(ctx context.Context, site *models.Site) ([]siteCoverageIssue, error) {
+	if site.CertID == nil {
+		return nil, nil
+	}
+
+	var cert models.Cert
+	if err := a.db.WithContext(ctx).Select("id", "domains").First(&cert, "id = ?", *site.CertID).Error; err != nil {
+		return nil, fmt.Errorf("failed to get cert: %w", err)
+	}
+
+	return siteCheckCoverage(site, cert.ID, cert.Domains), nil
+}
+
+// certCheckCoverage 检查使用这张证书的所有站点，用于证书域名变化时
+func (a *App) certCheckCoverage(ctx context.Context, certID uint, domains []string) ([]siteCoverageIssue, error) {
+	var sites []models.Site
+	if err := a.db.WithContext(ctx).Find(&sites, "cert_id = ?", certID).Error; err != nil {
+		return nil, fmt.Errorf("failed to get sites: %w", err)
+	}
+
+	var issues []siteCoverageIssue
+	for _, site := range sites {
+		issues = append(issues, siteCheckCoverage(&site, certID, domains)...)
+	}
+
+	return issues, nil
+}
+
+// certLogCoverage 续期之后不能拒绝新证书，只记录没有被覆盖的站点
+func (a *App) certLogCoverage(ctx context.Context, cert *models.Cert) {
+	issues, err := a.certCheckCoverage(ctx, cert.ID, cert.Domains)
+	if err != nil {
+		a.l.Error("failed to check cert coverage", zap.Uint("id", cert.ID), zap.Error(err))
+		return
+	}
+	for _, issue := range issues {
+		a.l.Warn("site hostname not covered by cert",
+			zap.Uint("certID", cert.ID),
+			zap.Uint("siteID", issue.SiteID),
+			zap.String("hostname", issue.Hostname),
+			zap.String("severity", issue.Severity),
+		)
+	}
+}
+
+func siteCoverageHasError(issues []siteCoverageIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == siteCoverageError {
+			return true
+		}
+	}
+	return false
+}
+
+// siteCoverageValidationErrors 把错误级别的覆盖问题转换为证书的验证错误
+func siteCoverageValidationErrors(issues []siteCoverageIssue) certutil.ValidationErrors {
+	var validationErrs certutil.ValidationErrors
+	for _, issue := range issues {
+		if issue.Severity != siteCoverageError {
+			continue
+		}
+		validationErrs = append(validationErrs, certutil.ValidationError{
+			Field:   "domains",
+			Code:    certutil.ValidationDomainMismatch,
+			Message: fmt.Sprintf("site %d (%s): %s", issue.SiteID, issue.SiteName, issue.Message),
+		})
+	}
+	return validationErrs
+}
+
+func siteCoverageMapIssues(issues []siteCoverageIssue) *[]admin.SiteCoverageIssue {
+	res := []admin.SiteCoverageIssue{}
+	for _, issue := range issues {
+		res = append(res, admin.SiteCoverageIssue{
+			SiteId:   &issue.SiteID,
+			SiteName: &issue.SiteName,
+			CertId:   &issue.CertID,
+			Hostname: &issue.Hostname,
+			Severity: &issue.Severity,
+			Message:  &issue.Message,
+		})
+	}
+	return &res
+}
@@ -1,55 +1,9 @@
 package handlers
 
-import (
-	"crypto/aes"
-	"crypto/cipher"
-	"crypto/rand"
-	"fmt"
-	"io"
-)
-
 func (a *App) aesDecrypt(encryptedData []byte) ([]byte, error) {
-	c, err := aes.NewCipher(a.esk)
-	if err != nil {
-		return nil, fmt.Errorf("could not create new cipher: %w", err)
-	}
-
-	gcm, err := cipher.NewGCM(c)
-	if err != nil {
-		return nil, fmt.Errorf("could not create GCM: %w", err)
-	}
-
-	nonceSize := gcm.NonceSize()
-	if len(encryptedData) < nonceSize {
-		return nil, fmt.Errorf("encrypted data too short")
-	}
-
-	nonce, ciphertext := encryptedData[:nonceSize], encryptedData[nonceSize:]
-	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
-	if err != nil {
-		return nil, fmt.Errorf("could not decrypt ciphertext: %w", err)
-	}
-
-	return plaintext, nil
+	return a.kr.Decrypt(encryptedData)
 }
 
 func (a *App) aesEncrypt(plaintext []byte) ([]byte, error) {
-	c, err := aes.NewCipher(a.esk)
-	if err != nil {
-		return nil, fmt.Errorf("could not create new cipher: %w", err)
-	}
-
-	gcm, err := cipher.NewGCM(c)
-	if err != nil {
-		return nil, fmt.Errorf("could not create GCM: %w", err)
-	}
-
-	nonce := make([]byte, gcm.NonceSize())
-
-	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
-		return nil, fmt.Errorf("could not generate nonce: %w", err)
-	}
-
-	ciphertext := gcm.Seal(nonce, nonce, plaintext, nil)
-	return ciphertext, nil
+	return a.kr.Encrypt(plaintext)
 }
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"caddy-delivery-network/app/server/certutil"
+	"caddy-delivery-network/app/server/constants"
+	"caddy-delivery-network/app/server/models"
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"time"
+)
+
+// StartCertRenewScheduler 在后台定期检查即将过期的自动管理证书并续期
+func (a *App) StartCertRenewScheduler(ctx context.Context, interval time.Duration, window time.Duration, backoff time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			a.certRenewScan(ctx, window, backoff)
+
+			select {
+			case <-ctx.Done():
+				a.l.Debug("stop cert renew scheduler")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (a *App) certRenewScan(ctx context.Context, window time.Duration, backoff time.Duration) {
+	// 找出所有在续期窗口内的自动管理证书（从未签发过的证书过期时间为零值，也会被选中）
+	var certs []models.Cert
+	if err := a.db.WithContext(ctx).
+		Where("provider IS NOT NULL AND expires_at < ?", time.Now().Add(window)).
+		Order("expires_at ASC").
+		Find(&certs).Error; err != nil {
+		a.l.Error("failed to find certs to renew", zap.Error(err))
+		return
+	}
+
+	for _, cert := range certs {
+		if ctx.Err() != nil {
+			return
+		}
+		if cert.ExpiresAt.After(time.Now().Add(certRenewWindow(&cert, window))) {
+			continue
+		}
+		a.certRenewScheduled(ctx, cert.ID, window, backoff)
+	}
+}
+
+func (a *App) certRenewScheduled(ctx context.Context, id uint, window time.Duration, backoff time.Duration) {
+	// 加锁，避免多个副本同时续期同一张证书
+	lockKey := fmt.Sprintf(constants.LockKeyCertRenew, id)
+	token, ok, err := a.lockAcquire(ctx, lockKey, constants.CertRenewLockTTL)
+	if err != nil {
+		a.l.Error("failed to acquire cert renew lock", zap.Uint("id", id), zap.Error(err))
+		return
+	} else if !ok {
+		a.l.Debug("cert is being renewed by another replica", zap.Uint("id", id))
+		return
+	}
+	defer func() {
+		if err := a.lockRelease(context.WithoutCancel(ctx), lockKey, token); err != nil {
+			a.l.Error("failed to release cert renew lock", zap.Uint("id", id), zap.Error(err))
+		}
+	}()
+
+	// 拿到锁之后重新读取，其他副本可能刚刚完成了续期
+	var cert models.Cert
+	if err := a.db.WithContext(ctx).First(&cert, "id = ?", id).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			a.l.Error("failed to get cert", zap.Uint("id", id), zap.Error(err))
+		}
+		return
+	}
+	if cert.Provider == nil || cert.ExpiresAt.After(time.Now().Add(certRenewWindow(&cert, window))) {
+		return
+	}
+
+	// 检查是否还在失败退避期内
+	nextAttempt, err := a.certRenewNextAttempt(ctx, id, backoff)
+	if err != nil {
+		a.l.Error("failed to get cert renew history", zap.Uint("id", id), zap.Error(err))
+		return
+	}
+	if time.Now().Before(nextAttempt) {
+		a.l.Debug("cert renew is backing off", zap.Uint("id", id), zap.Time("nextAttempt", nextAttempt))
+		return
+	}
+
+	if err := a.certRenewWithRecord(ctx, &cert, constants.CertRenewTriggerScheduled); err != nil {
+		a.l.Warn("scheduled cert renew failed", zap.Uint("id", id), zap.Error(err))
+	} else {
+		a.l.Info("scheduled cert renew succeeded", zap.Uint("id", id), zap.Time("expiresAt", cert.ExpiresAt))
+	}
+}
+
+// certRenewWindow 有效期较短的证书（例如内置 CA 签发的）在剩余三分之一有效期时续期，而不是使用统一的窗口
+func certRenewWindow(cert *models.Cert, window time.Duration) time.Duration {
+	certs, err := certutil.ParseCertificatesPEM([]byte(cert.Certificate))
+	if err != nil {
+		return window
+	}
+
+	if lifetime := certs[0].NotAfter.Sub(certs[0].NotBefore); lifetime/3 < window {
+		return lifetime / 3
+	}
+	return window
+}
+
+// certRenewNextAttempt 根据最近一次成功之后的连续失败次数，计算下一次允许尝试的时间
+func (a *App) certRenewNextAttempt(ctx context.Context, id uint, backoff time.Duration) (time.Time, error) {
+	var records []models.CertRenewRecord
+	if err := a.db.WithContext(ctx).
+		Where("cert_id = ?", id).
+		Order("id DESC").
+		Limit(16). // 超过这个次数之后间隔早就到达上限了
+		Find(&records).Error; err != nil {
+		return time.Time{}, err
+	}
+
+	var (
+		failures    int
+		lastFailure time.Time
+	)
+	for _, record := range records {
+		if record.Success {
+			break
+		}
+		if failures == 0 {
+			lastFailure = record.FinishedAt
+		}
+		failures++
+	}
+
+	if failures == 0 {
+		return time.Time{}, nil
+	}
+
+	delay := backoff << (failures - 1)
+	if delay <= 0 || delay > constants.CertRenewBackoffMax {
+		delay = constants.CertRenewBackoffMax
+	}
+
+	return lastFailure.Add(delay), nil
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"bytes"
+	"caddy-delivery-network/app/server/constants"
+	"caddy-delivery-network/app/server/gen/oapi/worker"
+	"caddy-delivery-network/app/server/models"
+	"caddy-delivery-network/app/server/utils"
+	"encoding/json"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+func (a *App) GetBundle(c echo.Context, id uint) error {
+	w := c.Get("instance").(*models.Instance)
+
+	rctx := c.Request().Context()
+
+	// 绑定请求体（可以为空，为空时返回所有内容）
+	var req worker.GetBundleJSONRequestBody
+	if err := c.Bind(&req); err != nil {
+		a.l.Error("failed to bind bundle request", zap.Uint("id", w.ID), zap.Error(err))
+		return c.NoContent(http.StatusBadRequest)
+	}
+
+	// 更新实例心跳时间
+	a.rdb.Set(rctx, fmt.Sprintf(constants.CacheKeyInstanceLastseen, w.ID), time.Now().Unix(), constants.CacheExpireInstanceLastseen)
+
+	// 准备配置与文件
+	configBytes, err := a.getInstanceConfig(rctx, w)
+	if err != nil {
+		a.l.Error("bundle get config", zap.Uint("id", w.ID), zap.Error(err))
+		return c.NoContent(http.StatusInternalServerError)
+	}
+	files, err := a.loadInstanceFiles(rctx, w)
+	if err != nil {
+		a.l.Error("bundle load files", zap.Uint("id", w.ID), zap.Error(err))
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	// 实例已经有的内容
+	known := make(map[string]string)
+	if req.Files != nil {
+		for _, f := range *req.Files {
+			known[f.Path] = f.Digest
+		}
+	}
+
+	// 清单包含所有文件，只有摘要不同的文件会放进文件包
+	manifest := worker.BundleManifest{
+		ConfigDigest: utils.Digest(configBytes),
+		Files:        []worker.BundleFile{},
+	}
+	manifest.ConfigIncluded = req.ConfigDigest == nil || *req.ConfigDigest != manifest.ConfigDigest
+	for i, f := range files {
+		bundleFile := worker.BundleFile{
+			Path:   f.Path,
+			Digest: f.Digest,
+		}
+		if known[f.Path] != f.Digest {
+			bundleFile.Entry = utils.P(fmt.Sprintf(constants.BundleFileEntry, i))
+		}
+		manifest.Files = append(manifest.Files, bundleFile)
+	}
+
+	manifestBytes, err := json.Marshal(&manifest)
+	if err != nil {
+		a.l.Error("bundle marshal manifest", zap.Uint("id", w.ID), zap.Error(err))
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	// 清单放在最前面，方便实例边读边处理
+	var buf bytes.Buffer
+	bw := newBundleWriter(&buf)
+	if err := bw.add(constants.BundleManifestName, manifestBytes, 0644); err != nil {
+		a.l.Error("bundle write manifest", zap.Uint("id", w.ID), zap.Error(err))
+		return c.NoContent(http.StatusInternalServerError)
+	}
+	if manifest.ConfigIncluded {
+		if err := bw.add(constants.BundleConfigName, configBytes, 0644); err != nil {
+			a.l.Error("bundle write config", zap.Uint("id", w.ID), zap.Error(err))
+			return c.NoContent(http.StatusInternalServerError)
+		}
+	}
+	for i, f := range files {
+		if manifest.Files[i].Entry == nil {
+			continue
+		}
+		if err := bw.add(*manifest.Files[i].Entry, f.Content, 0600); err != nil {
+			a.l.Error("bundle write file", zap.Uint("id", w.ID), zap.String("path", f.Path), zap.Error(err))
+			return c.NoContent(http.StatusInternalServerError)
+		}
+	}
+	if err := bw.close(); err != nil {
+		a.l.Error("bundle close", zap.Uint("id", w.ID), zap.Error(err))
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	return c.Blob(http.StatusOK, "application/gzip", buf.Bytes())
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"caddy-delivery-network/app/server/constants"
+	"caddy-delivery-network/app/server/models"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+func (a *App) Events(c echo.Context, id uint) error {
+	w := c.Get("instance").(*models.Instance)
+
+	rctx := c.Request().Context()
+
+	// 先注册再响应，避免错过建立连接期间的通知
+	notifyChan, unsubscribe := a.instanceSubscribe(w.ID)
+	defer unsubscribe()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no") // 避免被 nginx 之类的反向代理缓冲
+	res.WriteHeader(http.StatusOK)
+
+	// 告知重连间隔，连接建立后实例会主动同步一次，补上断线期间错过的变化
+	if _, err := fmt.Fprintf(res, "retry: %d\n\n", constants.InstanceEventsRetry.Milliseconds()); err != nil {
+		return nil
+	}
+	res.Flush()
+
+	keepalive := time.NewTicker(constants.InstanceEventsKeepalive)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-rctx.Done():
+			return nil
+		case <-notifyChan:
+			if _, err := fmt.Fprint(res, "event: update\ndata: {}\n\n"); err != nil {
+				return nil
+			}
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(res, ": keepalive\n\n"); err != nil {
+				return nil
+			}
+		}
+		res.Flush()
+	}
+}
@@ -2,7 +2,10 @@ package handlers
 
 import (
 	"caddy-delivery-network/app/server/constants"
+	"caddy-delivery-network/app/server/gen/oapi/worker"
 	"caddy-delivery-network/app/server/models"
+	"caddy-delivery-network/app/server/utils"
+	"context"
 	"errors"
 	"fmt"
 	"github.com/labstack/echo/v4"
@@ -11,30 +14,42 @@ import (
 	"net/http"
 )
 
-func (a *App) GetConfig(c echo.Context, id uint) error {
+// getInstanceConfig 读取缓存的配置，没有缓存时重新生成
+func (a *App) getInstanceConfig(ctx context.Context, w *models.Instance) ([]byte, error) {
+	if data, err := a.rdb.Get(ctx, fmt.Sprintf(constants.CacheKeyInstanceConfig, w.ID)).Bytes(); err == nil {
+		return data, nil
+	} else if !errors.Is(err, redis.Nil) {
+		a.l.Error("getconfig check cache", zap.Error(err))
+	}
+
+	// 产生结果
+	resString, err := a.buildInstanceConfigByModel(ctx, w)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build config: %w", err)
+	}
+
+	// 加入缓存
+	a.rdb.Set(ctx, fmt.Sprintf(constants.CacheKeyInstanceConfig, w.ID), resString, constants.CacheExpireInstanceConfig)
+
+	return []byte(resString), nil
+}
+
+func (a *App) GetConfig(c echo.Context, id uint, params worker.GetConfigParams) error {
 	w := c.Get("instance").(*models.Instance)
 
 	rctx := c.Request().Context()
 
-	// 检查是否有缓存结果
-	var resBytes []byte
-	if data, err := a.rdb.Get(rctx, fmt.Sprintf(constants.CacheKeyInstanceConfig, w.ID)).Bytes(); err != nil {
-		if !errors.Is(err, redis.Nil) {
-			a.l.Error("getconfig check cache", zap.Error(err))
-		}
-
-		// 产生结果
-		resString, err := a.buildInstanceConfigByModel(rctx, w)
-		if err != nil {
-			a.l.Error("getconfig build config", zap.Error(err))
-			return c.NoContent(http.StatusInternalServerError)
-		}
-		resBytes = []byte(resString)
-
-		// 加入缓存
-		a.rdb.Set(rctx, fmt.Sprintf(constants.CacheKeyInstanceConfig, w.ID), resString, constants.CacheExpireInstanceConfig)
-	} else {
-		resBytes = data
+	resBytes, err := a.getInstanceConfig(rctx, w)
+	if err != nil {
+		a.l.Error("getconfig build config", zap.Error(err))
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	// 实例已经有相同的配置时不用再传输
+	digest := utils.Digest(resBytes)
+	c.Response().Header().Set("ETag", utils.ETag(digest))
+	if params.IfNoneMatch != nil && utils.ETagMatch(*params.IfNoneMatch, digest) {
+		return c.NoContent(http.StatusNotModified)
 	}
 
 	// 使用结果响应
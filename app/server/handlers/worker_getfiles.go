@@ -5,6 +5,7 @@ import (
 	"caddy-delivery-network/app/server/gen/oapi/worker"
 	"caddy-delivery-network/app/server/models"
 	"caddy-delivery-network/app/server/types"
+	"caddy-delivery-network/app/server/utils"
 	"context"
 	"encoding/json"
 	"errors"
@@ -59,6 +60,14 @@ func (a *App) buildInstanceFileListByModel(ctx context.Context, instance *models
 		}
 	}
 
+	// 添加作为信任根的 CA 证书
+	for _, caID := range instance.CAIDs {
+		filesMap[caFilePath(uint(caID))] = types.CacheInstanceFile{
+			Type: types.CacheInstanceFileCA,
+			ID:   uint(caID),
+		}
+	}
+
 	// 依据 site 添加 certs
 	for _, siteID := range instance.SiteIDs {
 		var site models.Site
@@ -71,7 +80,7 @@ func (a *App) buildInstanceFileListByModel(ctx context.Context, instance *models
 			return nil, fmt.Errorf("heartbeat get site: %w", err)
 		}
 
-		if site.Cert != nil {
+		if site.Cert != nil && certIsIssued(site.Cert) { // 还没有签发的证书不下发
 			certPathPrefix := fmt.Sprintf(constants.CertPathDir, site.Cert.ID)
 
 			filesMap[certPathPrefix+constants.CertPathCertName] = types.CacheInstanceFile{
@@ -137,6 +146,15 @@ func (a *App) getFileByMeta(ctx context.Context, fileMeta *types.CacheInstanceFi
 			return nil, fmt.Errorf("unsupported subtype %d", fileMeta.Subtype)
 		}
 
+	case types.CacheInstanceFileCA: // 是 CA 证书
+		var ca models.CertAuthority
+		if err := a.db.WithContext(ctx).First(&ca, "id = ?", fileMeta.ID).Error; err != nil {
+			a.l.Error("get ca", zap.Any("meta", fileMeta), zap.Error(err))
+			return nil, fmt.Errorf("failed to get ca: %w", err)
+		}
+
+		return []byte(ca.Certificate), nil
+
 	default: // 这是个啥
 		return nil, fmt.Errorf("unsupported type %d", fileMeta.Type)
 	}
@@ -193,5 +211,12 @@ func (a *App) GetFiles(c echo.Context, id uint, params worker.GetFilesParams) er
 		return c.NoContent(http.StatusNotFound)
 	}
 
+	// 实例已经有相同的文件时不用再传输
+	digest := utils.Digest(fileBytes)
+	c.Response().Header().Set("ETag", utils.ETag(digest))
+	if params.IfNoneMatch != nil && utils.ETagMatch(*params.IfNoneMatch, digest) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
 	return c.Blob(http.StatusOK, echo.MIMEOctetStream, fileBytes)
 }
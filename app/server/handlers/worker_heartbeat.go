@@ -4,6 +4,7 @@ import (
 	"caddy-delivery-network/app/server/constants"
 	"caddy-delivery-network/app/server/gen/oapi/worker"
 	"caddy-delivery-network/app/server/models"
+	"caddy-delivery-network/app/server/utils"
 	"context"
 	"encoding/json"
 	"errors"
@@ -34,6 +35,23 @@ func (a *App) buildHeartbeatData(ctx context.Context, w *models.Instance) ([]byt
 		res.FilesUpdatedAt = append(res.FilesUpdatedAt, worker.FileUpdateRecord{
 			Path:      constants.AFilePathPrefix + aFile.Filename,
 			UpdatedAt: aFileUpdatedAt,
+			Digest:    utils.Digest(aFile.Content),
+		})
+	}
+
+	// 检查作为信任根的 CA 证书
+	for _, caID := range w.CAIDs {
+		var ca models.CertAuthority
+		if err := a.db.WithContext(ctx).First(&ca, "id = ?", caID).Error; err != nil {
+			// CA 记录拉取出错
+			a.l.Error("heartbeat get ca", zap.Uint("caID", uint(caID)), zap.Error(err))
+			return nil, fmt.Errorf("failed to get ca: %w", err)
+		}
+
+		res.FilesUpdatedAt = append(res.FilesUpdatedAt, worker.FileUpdateRecord{
+			Path:      caFilePath(ca.ID),
+			UpdatedAt: ca.UpdatedAt.Unix(),
+			Digest:    utils.Digest([]byte(ca.Certificate)),
 		})
 	}
 
@@ -51,22 +69,31 @@ func (a *App) buildHeartbeatData(ctx context.Context, w *models.Instance) ([]byt
 		}
 
 		// 证书文件
-		if site.Cert != nil {
+		if site.Cert != nil && certIsIssued(site.Cert) { // 还没有签发的证书不下发
 			certPathPrefix := fmt.Sprintf(constants.CertPathDir, site.Cert.ID)
+			// 私钥的摘要需要按照解密后的内容计算
+			keyBytes, err := a.aesDecrypt(site.Cert.PrivateKey)
+			if err != nil {
+				a.l.Error("heartbeat decrypt cert", zap.Uint("certID", site.Cert.ID), zap.Error(err))
+				return nil, fmt.Errorf("failed to decrypt cert: %w", err)
+			}
 			// 基础信息（证书与私钥）
 			res.FilesUpdatedAt = append(res.FilesUpdatedAt, worker.FileUpdateRecord{
 				Path:      certPathPrefix + constants.CertPathCertName,
 				UpdatedAt: site.Cert.UpdatedAt.Unix(),
+				Digest:    utils.Digest([]byte(site.Cert.Certificate)),
 			})
 			res.FilesUpdatedAt = append(res.FilesUpdatedAt, worker.FileUpdateRecord{
 				Path:      certPathPrefix + constants.CertPathKeyName,
 				UpdatedAt: site.Cert.UpdatedAt.Unix(),
+				Digest:    utils.Digest(keyBytes),
 			})
 			// 中间证书
 			if site.Cert.IntermediateCertificate != "" {
 				res.FilesUpdatedAt = append(res.FilesUpdatedAt, worker.FileUpdateRecord{
 					Path:      certPathPrefix + constants.CertPathIntermediateName,
 					UpdatedAt: site.Cert.UpdatedAt.Unix(),
+					Digest:    utils.Digest([]byte(site.Cert.IntermediateCertificate)),
 				})
 			}
 		}
@@ -85,6 +112,14 @@ func (a *App) buildHeartbeatData(ctx context.Context, w *models.Instance) ([]byt
 	// 确认时间
 	res.ConfigUpdatedAt = configUpdatedAt
 
+	// 配置的摘要，实例依据它判断是否需要重新加载
+	configBytes, err := a.getInstanceConfig(ctx, w)
+	if err != nil {
+		a.l.Error("heartbeat get config", zap.Error(err))
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+	res.ConfigDigest = utils.Digest(configBytes)
+
 	resBytes, err := json.Marshal(res)
 	if err != nil {
 		a.l.Error("heartbeat json marshal", zap.Any("res", res), zap.Error(err))
@@ -102,6 +137,9 @@ func (a *App) Heartbeat(c echo.Context, id uint) error {
 	// 更新实例心跳时间
 	a.rdb.Set(rctx, fmt.Sprintf(constants.CacheKeyInstanceLastseen, w.ID), time.Now().Unix(), constants.CacheExpireInstanceLastseen)
 
+	// 空闲的实例很久都不会再上报，只要还在心跳就保留最近一次的同步结果
+	a.rdb.Expire(rctx, fmt.Sprintf(constants.CacheKeyInstanceReport, w.ID), constants.CacheExpireInstanceReport)
+
 	// 检查是否有缓存结果
 	var resBytes []byte
 	cacheKey := fmt.Sprintf(constants.CacheKeyInstanceHeartbeat, w.ID)
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"caddy-delivery-network/app/server/constants"
+	"caddy-delivery-network/app/server/gen/oapi/worker"
+	"caddy-delivery-network/app/server/models"
+	"caddy-delivery-network/app/server/types"
+	"encoding/json"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+func (a *App) Report(c echo.Context, id uint) error {
+	w := c.Get("instance").(*models.Instance)
+
+	rctx := c.Request().Context()
+
+	// 绑定请求体
+	var req worker.ReportJSONRequestBody
+	if err := c.Bind(&req); err != nil {
+		a.l.Error("failed to bind report", zap.Uint("id", w.ID), zap.Error(err))
+		return c.NoContent(http.StatusBadRequest)
+	}
+
+	now := time.Now().Unix()
+
+	// 上报也算一次通信
+	a.rdb.Set(rctx, fmt.Sprintf(constants.CacheKeyInstanceLastseen, w.ID), now, constants.CacheExpireInstanceLastseen)
+
+	reportBytes, err := json.Marshal(&types.CacheInstanceReport{
+		Report:     req,
+		ReceivedAt: now,
+	})
+	if err != nil {
+		a.l.Error("failed to marshal report", zap.Uint("id", w.ID), zap.Error(err))
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	// 配置漂移单独保存
+	if req.Drift != nil {
+		a.l.Warn("instance reported config drift", zap.Uint("id", w.ID), zap.Strings("differences", req.Drift.Differences), zap.Bool("reapplied", req.Drift.Reapplied))
+		if err := a.rdb.Set(rctx, fmt.Sprintf(constants.CacheKeyInstanceDrift, w.ID), reportBytes, constants.CacheExpireInstanceReport).Err(); err != nil {
+			a.l.Error("failed to save drift", zap.Uint("id", w.ID), zap.Error(err))
+			return c.NoContent(http.StatusInternalServerError)
+		}
+	}
+	// 只保留最近一次的同步结果，只包含漂移的上报（没有文件同步结果）不覆盖它
+	if req.Drift == nil || req.Files != nil {
+		if err := a.rdb.Set(rctx, fmt.Sprintf(constants.CacheKeyInstanceReport, w.ID), reportBytes, constants.CacheExpireInstanceReport).Err(); err != nil {
+			a.l.Error("failed to save report", zap.Uint("id", w.ID), zap.Error(err))
+			return c.NoContent(http.StatusInternalServerError)
+		}
+	}
+
+	if req.Status != "ok" {
+		a.l.Warn("instance reported sync failure", zap.Uint("id", w.ID), zap.Stringp("error", req.Error))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
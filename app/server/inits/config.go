@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 )
 
+const defaultEncryptKeyID = "default"
+
 func Config() (*config.Config, error) {
 	// 手动配置映射，如果这里有什么自动映射工具就好了， viper 好像处理这种基于环境变量的配置也不是很方便
 	var cfg config.Config
@@ -34,10 +37,41 @@ func Config() (*config.Config, error) {
 		cfg.System.RedisConnectionString = redisconn
 	}
 
-	if encsk, exist := os.LookupEnv("ENCRYPT_SECRET_KEY"); !exist {
-		return nil, fmt.Errorf("ENCRYPT_SECRET_KEY environment variable not set")
-	} else {
-		cfg.Security.EncryptSecretKey = encsk
+	if publicEp, exist := os.LookupEnv("PUBLIC_ENDPOINT"); exist {
+		cfg.System.PublicEndpoint = strings.TrimSuffix(publicEp, "/")
+	}
+
+	// 加密密钥： ENCRYPT_SECRET_KEY 是最初的单个密钥（ ID 为 default ），
+	// 轮换时在 ENCRYPT_SECRET_KEYS 中以 id:key,id:key 的格式添加新密钥，并用 ENCRYPT_PRIMARY_KEY_ID 指定加密使用的密钥
+	cfg.Security.EncryptSecretKeys = map[string]string{}
+	if encsk, exist := os.LookupEnv("ENCRYPT_SECRET_KEY"); exist && encsk != "" {
+		cfg.Security.EncryptSecretKeys[defaultEncryptKeyID] = encsk
+		cfg.Security.EncryptLegacyKeyID = defaultEncryptKeyID
+		cfg.Security.EncryptPrimaryKeyID = defaultEncryptKeyID
+	}
+	if encsks, exist := os.LookupEnv("ENCRYPT_SECRET_KEYS"); exist && encsks != "" {
+		for _, pair := range strings.Split(encsks, ",") {
+			id, key, found := strings.Cut(pair, ":")
+			if !found || id == "" || key == "" {
+				return nil, fmt.Errorf("ENCRYPT_SECRET_KEYS should be in id:key,id:key format")
+			}
+			if _, dup := cfg.Security.EncryptSecretKeys[id]; dup {
+				return nil, fmt.Errorf("duplicated encrypt key id %s", id)
+			}
+			cfg.Security.EncryptSecretKeys[id] = key
+			if cfg.Security.EncryptPrimaryKeyID == "" {
+				cfg.Security.EncryptPrimaryKeyID = id
+			}
+		}
+	}
+	if len(cfg.Security.EncryptSecretKeys) == 0 {
+		return nil, fmt.Errorf("ENCRYPT_SECRET_KEY or ENCRYPT_SECRET_KEYS environment variable not set")
+	}
+	if primary, exist := os.LookupEnv("ENCRYPT_PRIMARY_KEY_ID"); exist && primary != "" {
+		if _, ok := cfg.Security.EncryptSecretKeys[primary]; !ok {
+			return nil, fmt.Errorf("ENCRYPT_PRIMARY_KEY_ID %s not found in encrypt keys", primary)
+		}
+		cfg.Security.EncryptPrimaryKeyID = primary
 	}
 
 	if sigsk, exist := os.LookupEnv("SIGNATURE_SECRET_KEY"); !exist {
@@ -46,5 +80,29 @@ func Config() (*config.Config, error) {
 		cfg.Security.SignatureSecretKey = sigsk
 	}
 
+	if interval, exist := os.LookupEnv("CERT_RENEW_INTERVAL"); !exist {
+		cfg.CertRenew.Interval = 1 * time.Hour // 默认每小时检查一次
+	} else if d, err := time.ParseDuration(interval); err != nil || d <= 0 {
+		return nil, fmt.Errorf("CERT_RENEW_INTERVAL should be a valid positive duration")
+	} else {
+		cfg.CertRenew.Interval = d
+	}
+
+	if window, exist := os.LookupEnv("CERT_RENEW_WINDOW"); !exist {
+		cfg.CertRenew.Window = 30 * 24 * time.Hour // 默认过期前 30 天开始续期
+	} else if d, err := time.ParseDuration(window); err != nil || d <= 0 {
+		return nil, fmt.Errorf("CERT_RENEW_WINDOW should be a valid positive duration")
+	} else {
+		cfg.CertRenew.Window = d
+	}
+
+	if backoff, exist := os.LookupEnv("CERT_RENEW_BACKOFF"); !exist {
+		cfg.CertRenew.Backoff = 15 * time.Minute
+	} else if d, err := time.ParseDuration(backoff); err != nil || d <= 0 {
+		return nil, fmt.Errorf("CERT_RENEW_BACKOFF should be a valid positive duration")
+	} else {
+		cfg.CertRenew.Backoff = d
+	}
+
 	return &cfg, nil
 }
@@ -6,6 +6,8 @@ import (
 	"github.com/alexedwards/argon2id"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"regexp"
+	"strings"
 )
 
 func DB(conn string) (db *gorm.DB, err error) {
@@ -24,6 +26,11 @@ func DB(conn string) (db *gorm.DB, err error) {
 		return nil, fmt.Errorf("failed to init data into database: %w", err)
 	}
 
+	// 迁移已有的模板
+	if err = migTemplates(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate templates: %w", err)
+	}
+
 	// 返回
 	return db, nil
 }
@@ -36,6 +43,12 @@ func mig(db *gorm.DB) error {
 		&models.Site{},
 		&models.AdditionalFile{},
 		&models.Instance{},
+		&models.AcmeAccount{},
+		&models.CertRenewRecord{},
+		&models.CertExportRecord{},
+		&models.CertAuthority{},
+		&models.CertVersion{},
+		&models.DataMigration{},
 	)
 }
 
@@ -73,25 +86,25 @@ func initData(db *gorm.DB) (err error) {
 			{
 				Name:        "空白模板",
 				Description: "没有内置任何内容，一切自定义",
-				Content:     "{{.Origin}} {\n    {{.Cert}}\n{{.Content}}\n}",
+				Content:     "{{.Origin}} {\n    {{.Cert}}\n    {{.ACMEChallenge}}\n{{.Content}}\n}",
 				Variables:   []string{"Content"},
 			},
 			{
 				Name:        "简单反代",
 				Description: "简单的反向代理，使用 Caddy 内置的证书管理",
-				Content:     "{{.Origin}} {\n    {{.Cert}}\n    reverse_proxy {{.Source}}\n}",
+				Content:     "{{.Origin}} {\n    {{.Cert}}\n    {{.ACMEChallenge}}\n    reverse_proxy {{.Source}}\n}",
 				Variables:   []string{"Source"},
 			},
 			{
 				Name:        "变源反代",
 				Description: "使用源站（HTTPS）不知道的 SNI 做代理",
-				Content:     "{{.Origin}} {\n    {{.Cert}}\n    reverse_proxy https://{{.Source}} {\n        header_up Host {{.Source}}\n        transport http {\n            tls\n            tls_server_name {{.Source}}\n        }\n    }\n}",
+				Content:     "{{.Origin}} {\n    {{.Cert}}\n    {{.ACMEChallenge}}\n    reverse_proxy https://{{.Source}} {\n        header_up Host {{.Source}}\n        transport http {\n            tls\n            tls_server_name {{.Source}}\n        }\n    }\n}",
 				Variables:   []string{"Source"},
 			},
 			{
 				Name:        "自定义错误转换",
 				Description: "使用自定义的 502 错误页面",
-				Content:     "{{.Origin}} {\n    {{.Cert}}\n    reverse_proxy {{.Source}}\n    handle_errors {\n        @badgateway expression `{err.status_code} == 502`\n        handle @badgateway {\n            rewrite * /custom_502.html\n            file_server {\n                status 500\n            }\n        }\n    }\n}",
+				Content:     "{{.Origin}} {\n    {{.Cert}}\n    {{.ACMEChallenge}}\n    reverse_proxy {{.Source}}\n    handle_errors {\n        @badgateway expression `{err.status_code} == 502`\n        handle @badgateway {\n            rewrite * /custom_502.html\n            file_server {\n                status 500\n            }\n        }\n    }\n}",
 				Variables:   []string{"Source"},
 			},
 		}).Error; err != nil {
@@ -102,3 +115,57 @@ func initData(db *gorm.DB) (err error) {
 	// 已有数据或全部导入成功
 	return nil
 }
+
+// dataMigTemplatesACMEChallenge 给已有模板补上 {{.ACMEChallenge}} 的数据迁移
+const dataMigTemplatesACMEChallenge = "templates-acme-challenge"
+
+var (
+	templateCertPattern          = regexp.MustCompile(`\{\{-?\s*\.Cert\s*-?\}\}`)
+	templateACMEChallengePattern = regexp.MustCompile(`\{\{-?\s*\.ACMEChallenge\s*-?\}\}`)
+)
+
+// migTemplates 给引用了 {{.Cert}} 但没有引用 {{.ACMEChallenge}} 的模板补上验证请求的转发，
+// 没有由 server 通过 ACME 签发证书的站点这个字段为空，不影响渲染结果。
+// 只执行一次，之后管理员可以自行从模板中移除
+func migTemplates(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 检查是否已经执行过
+		var counter int64
+		if err := tx.Model(&models.DataMigration{}).Where("name = ?", dataMigTemplatesACMEChallenge).Count(&counter).Error; err != nil {
+			return fmt.Errorf("failed to get data migration record: %w", err)
+		} else if counter > 0 {
+			return nil
+		}
+
+		var templates []models.Template
+		if err := tx.Find(&templates).Error; err != nil {
+			return fmt.Errorf("failed to get templates: %w", err)
+		}
+
+		for _, template := range templates {
+			loc := templateCertPattern.FindStringIndex(template.Content)
+			if loc == nil || templateACMEChallengePattern.MatchString(template.Content) {
+				continue
+			}
+
+			// 与 {{.Cert}} 使用相同的缩进
+			lineStart := strings.LastIndex(template.Content[:loc[0]], "\n") + 1
+			indent := template.Content[lineStart:loc[0]]
+			if strings.TrimSpace(indent) != "" {
+				indent = ""
+			}
+			content := template.Content[:loc[1]] + "\n" + indent + "{{.ACMEChallenge}}" + template.Content[loc[1]:]
+
+			if err := tx.Model(&template).Update("content", content).Error; err != nil {
+				return fmt.Errorf("failed to update template %d: %w", template.ID, err)
+			}
+		}
+
+		// 记录已经执行
+		if err := tx.Create(&models.DataMigration{Name: dataMigTemplatesACMEChallenge}).Error; err != nil {
+			return fmt.Errorf("failed to create data migration record: %w", err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,138 @@
+package keyring
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// 带版本的密文格式： magic (4) | 版本 (1) | 密钥 ID 长度 (1) | 密钥 ID | nonce | 密文
+// 没有 magic 的是早期只有一个密钥时写入的密文，使用 legacy 密钥解密
+var magic = []byte("CDNE")
+
+const formatVersion = 1
+
+type Keyring struct {
+	primary string
+	legacy  string
+	ciphers map[string]cipher.AEAD
+}
+
+// New 创建密钥环， primary 为加密新数据使用的密钥， legacy 为没有版本信息的旧密文使用的密钥（可以为空）
+func New(keys map[string]string, primary string, legacy string) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no key provided")
+	}
+
+	k := &Keyring{
+		primary: primary,
+		legacy:  legacy,
+		ciphers: make(map[string]cipher.AEAD, len(keys)),
+	}
+	for id, key := range keys {
+		if id == "" || len(id) > 255 {
+			return nil, fmt.Errorf("invalid key id %q", id)
+		}
+		c, err := aes.NewCipher([]byte(key))
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", id, err)
+		}
+		gcm, err := cipher.NewGCM(c)
+		if err != nil {
+			return nil, fmt.Errorf("could not create GCM for key %q: %w", id, err)
+		}
+		k.ciphers[id] = gcm
+	}
+
+	if _, ok := k.ciphers[primary]; !ok {
+		return nil, fmt.Errorf("primary key %q not found", primary)
+	}
+	if _, ok := k.ciphers[legacy]; legacy != "" && !ok {
+		return nil, fmt.Errorf("legacy key %q not found", legacy)
+	}
+
+	return k, nil
+}
+
+func (k *Keyring) Primary() string {
+	return k.primary
+}
+
+// KeyID 返回加密这段数据使用的密钥， legacy 格式返回空字符串
+func (k *Keyring) KeyID(data []byte) string {
+	id, _, ok := parse(data)
+	if !ok {
+		return ""
+	}
+	return id
+}
+
+func (k *Keyring) Encrypt(plaintext []byte) ([]byte, error) {
+	gcm := k.ciphers[k.primary]
+
+	header := make([]byte, 0, len(magic)+2+len(k.primary))
+	header = append(header, magic...)
+	header = append(header, formatVersion, byte(len(k.primary)))
+	header = append(header, k.primary...)
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("could not generate nonce: %w", err)
+	}
+
+	// 头部作为附加数据，避免密钥 ID 被篡改
+	out := append(header, nonce...)
+	return gcm.Seal(out, nonce, plaintext, header), nil
+}
+
+func (k *Keyring) Decrypt(data []byte) ([]byte, error) {
+	if id, body, ok := parse(data); ok {
+		if gcm, exist := k.ciphers[id]; exist {
+			header := data[:len(data)-len(body)]
+			if plaintext, err := open(gcm, body, header); err == nil {
+				return plaintext, nil
+			} else if k.legacy == "" {
+				return nil, err
+			}
+		} else if k.legacy == "" {
+			return nil, fmt.Errorf("key %q is not available", id)
+		}
+		// 极小概率是恰好以 magic 开头的旧密文，继续尝试 legacy 密钥
+	}
+
+	if k.legacy == "" {
+		return nil, errors.New("data is not versioned and no legacy key is configured")
+	}
+	return open(k.ciphers[k.legacy], data, nil)
+}
+
+func parse(data []byte) (id string, body []byte, ok bool) {
+	if len(data) < len(magic)+2 || !bytes.Equal(data[:len(magic)], magic) || data[len(magic)] != formatVersion {
+		return "", nil, false
+	}
+	idLen := int(data[len(magic)+1])
+	start := len(magic) + 2
+	if idLen == 0 || len(data) < start+idLen {
+		return "", nil, false
+	}
+	return string(data[start : start+idLen]), data[start+idLen:], true
+}
+
+func open(gcm cipher.AEAD, data []byte, additionalData []byte) ([]byte, error) {
+	nonceSize := gcm.NonceSize()
+	if len(data) < nonceSize {
+		return nil, errors.New("encrypted data too short")
+	}
+
+	nonce, ciphertext := data[:nonceSize], data[nonceSize:]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt ciphertext: %w", err)
+	}
+
+	return plaintext, nil
+}
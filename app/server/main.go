@@ -7,7 +7,9 @@ import (
 	"caddy-delivery-network/app/server/handlers"
 	"caddy-delivery-network/app/server/inits"
 	"caddy-delivery-network/app/server/jwt"
+	"caddy-delivery-network/app/server/keyring"
 	"caddy-delivery-network/app/server/middlewares"
+	"context"
 	"embed"
 	"fmt"
 	"github.com/labstack/echo/v4"
@@ -54,8 +56,14 @@ func main() {
 		l.Fatal("error initializing JWT", zap.Error(err))
 	}
 
+	// 初始化加密密钥环
+	kr, err := keyring.New(cfg.Security.EncryptSecretKeys, cfg.Security.EncryptPrimaryKeyID, cfg.Security.EncryptLegacyKeyID)
+	if err != nil {
+		l.Fatal("error initializing keyring", zap.Error(err))
+	}
+
 	// 准备 handler app
-	handlerApp := handlers.NewApp(l, db, rdb, j, cfg.Security.EncryptSecretKey)
+	handlerApp := handlers.NewApp(l, db, rdb, j, kr, cfg.System.PublicEndpoint)
 
 	// 准备 echo 服务
 	e := echo.New()
@@ -74,6 +82,9 @@ func main() {
 	apiGroupWorker.Use(middlewares.WorkerAuth(db, rdb, l))
 	worker.RegisterHandlers(apiGroupWorker, handlerApp)
 
+	// ACME HTTP-01 验证（实例会把所有站点的验证请求转发过来）
+	e.GET("/.well-known/acme-challenge/:token", handlerApp.AcmeHTTP01Challenge)
+
 	// 添加 API 文档
 	if !cfg.System.IsProd {
 		if swg, err := admin.GetSwagger(); err != nil {
@@ -100,6 +111,12 @@ func main() {
 		Filesystem: http.FS(embeddedWeb),
 	}))
 
+	// 启动证书自动续期
+	handlerApp.StartCertRenewScheduler(context.Background(), cfg.CertRenew.Interval, cfg.CertRenew.Window, cfg.CertRenew.Backoff)
+
+	// 启动实例通知转发
+	handlerApp.StartInstanceNotifyListener(context.Background())
+
 	// 启动 echo 服务
 	if err := e.Start(cfg.System.Listen); err != nil {
 		l.Fatal("shutting down the server", zap.Error(err))
@@ -0,0 +1,12 @@
+package models
+
+import "gorm.io/gorm"
+
+type AcmeAccount struct {
+	gorm.Model
+
+	DirectoryURL string `gorm:"column:directory_url;uniqueIndex:idx_acme_account"` // ACME 目录地址
+	Email        string `gorm:"column:email;uniqueIndex:idx_acme_account"`         // 注册邮箱，同一个 CA 下与目录地址共同确定一个账户
+	URI          string `gorm:"column:uri"`                                        // CA 分配的账户地址 (KID)
+	PrivateKey   []byte `gorm:"column:private_key;type:bytea"`                     // 账户私钥，与证书私钥一样加密存储
+}
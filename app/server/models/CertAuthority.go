@@ -0,0 +1,17 @@
+package models
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type CertAuthority struct {
+	gorm.Model
+
+	Name      string    `gorm:"column:name"`            // CA 的名字，方便记忆
+	ParentID  *uint     `gorm:"column:parent_id;index"` // 签发这张 CA 证书的上级 CA ， NULL 表示根证书（或者上级不在系统中）
+	ExpiresAt time.Time `gorm:"column:expires_at"`      // CA 证书的过期时间
+
+	Certificate string `gorm:"column:certificate"`            // CA 证书
+	PrivateKey  []byte `gorm:"column:private_key;type:bytea"` // 私钥，与证书私钥一样加密存储； NULL 表示只导入了证书，不能用于签发
+}
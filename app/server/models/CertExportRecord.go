@@ -0,0 +1,12 @@
+package models
+
+import "gorm.io/gorm"
+
+type CertExportRecord struct {
+	gorm.Model
+
+	CertID uint   `gorm:"column:cert_id;index"` // 导出的证书
+	UserID uint   `gorm:"column:user_id"`       // 操作的用户
+	Format string `gorm:"column:format"`        // 导出格式： pem / zip / pkcs12
+	IP     string `gorm:"column:ip"`            // 请求来源
+}
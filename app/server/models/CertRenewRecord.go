@@ -0,0 +1,17 @@
+package models
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type CertRenewRecord struct {
+	gorm.Model
+
+	CertID     uint      `gorm:"column:cert_id;index"` // 续期的证书
+	Trigger    string    `gorm:"column:trigger"`       // 触发方式： manual 手动 / scheduled 自动
+	StartedAt  time.Time `gorm:"column:started_at"`    // 开始时间
+	FinishedAt time.Time `gorm:"column:finished_at"`   // 结束时间
+	Success    bool      `gorm:"column:success"`       // 是否成功
+	Error      string    `gorm:"column:error"`         // 失败原因
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"time"
+)
+
+// CertVersion 证书内容的历史版本，只追加不修改
+type CertVersion struct {
+	gorm.Model
+
+	CertID  uint   `gorm:"column:cert_id;uniqueIndex:idx_cert_version"` // 所属的证书
+	Version uint   `gorm:"column:version;uniqueIndex:idx_cert_version"` // 版本号，同一张证书内从 1 开始递增
+	Source  string `gorm:"column:source"`                               // 来源： upload / import / renew / rollback / snapshot
+
+	// 从证书中解析的信息，方便查看
+	Issuer    string         `gorm:"column:issuer"`
+	Serial    string         `gorm:"column:serial"`
+	NotBefore time.Time      `gorm:"column:not_before"`
+	NotAfter  time.Time      `gorm:"column:not_after"`
+	Domains   pq.StringArray `gorm:"column:domains;type:text[]"`
+
+	// 证书的本体信息，与 Cert 相同
+	Certificate             string `gorm:"column:certificate"`
+	PrivateKey              []byte `gorm:"column:private_key;type:bytea"` // 与 Cert.PrivateKey 一样加密存储
+	IntermediateCertificate string `gorm:"column:intermediate_certificate"`
+	CSR                     string `gorm:"column:csr"`
+}
@@ -0,0 +1,9 @@
+package models
+
+import "gorm.io/gorm"
+
+type DataMigration struct {
+	gorm.Model
+
+	Name string `gorm:"column:name;uniqueIndex"` // 数据迁移的名字，存在记录即表示已经执行过
+}
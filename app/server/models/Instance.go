@@ -17,4 +17,5 @@ type Instance struct {
 
 	AdditionalFileIDs pq.Int64Array `gorm:"column:additional_file_ids;type:integer[];index"` // 使用到的额外文件
 	SiteIDs           pq.Int64Array `gorm:"column:site_ids;type:integer[];index"`            // 部署在实例上的站点
+	CAIDs             pq.Int64Array `gorm:"column:ca_ids;type:integer[];index"`              // 作为信任根下发到实例上的 CA 证书
 }
@@ -5,6 +5,7 @@ type CacheInstanceFileType int
 const (
 	CacheInstanceFileAdditionalFile CacheInstanceFileType = iota
 	CacheInstanceFileCert
+	CacheInstanceFileCA
 )
 
 type CacheInstanceFileSubtype int
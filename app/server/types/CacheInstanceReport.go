@@ -0,0 +1,8 @@
+package types
+
+import "caddy-delivery-network/app/server/gen/oapi/worker"
+
+type CacheInstanceReport struct {
+	Report     worker.SyncReport `json:"report"`
+	ReceivedAt int64             `json:"received_at"`
+}
@@ -0,0 +1,33 @@
+package types
+
+import "encoding/json"
+
+type CertProviderType string
+
+const (
+	CertProviderACME       CertProviderType = "acme"
+	CertProviderInternalCA CertProviderType = "internal-ca"
+)
+
+// CertProvider 存放在 Cert.Provider 中的提供方配置
+type CertProvider struct {
+	Type CertProviderType `json:"type"`
+
+	// ACME
+	DirectoryURL string           `json:"directory_url,omitempty"` // ACME 目录地址
+	Email        string           `json:"email,omitempty"`         // 账户联系邮箱
+	EAB          *CertProviderEAB `json:"eab,omitempty"`           // 外部账户绑定
+	KeyType      string           `json:"key_type,omitempty"`      // 证书私钥类型： ec256 (默认) / ec384 / rsa2048 / rsa4096
+	TrustedRoots string           `json:"trusted_roots,omitempty"` // 额外信任的 CA 根证书（ PEM ），测试环境使用
+	Challenge    string           `json:"challenge,omitempty"`     // 优先使用的验证方式： http-01 / dns-01 ，通配符域名只能使用 dns-01
+	DNS          json.RawMessage  `json:"dns,omitempty"`           // DNS-01 提供方配置，由其中的 type 字段选择提供方
+
+	// 内置 CA （私钥类型同样使用 KeyType ）
+	CAID     uint   `json:"ca_id,omitempty"`    // 签发使用的 CA
+	Validity string `json:"validity,omitempty"` // 证书有效期，默认 168h
+}
+
+type CertProviderEAB struct {
+	KID     string `json:"kid"`
+	HMACKey string `json:"hmac_key"` // base64url 编码
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Digest 计算内容的 SHA-256 摘要，实例用它判断文件与配置是否需要更新
+func Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func ETag(digest string) string {
+	return `"` + digest + `"`
+}
+
+// ETagMatch 检查 If-None-Match 中是否有与摘要相同的 ETag
+func ETagMatch(ifNoneMatch string, digest string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+		if strings.Trim(strings.TrimPrefix(tag, "W/"), `"`) == digest {
+			return true
+		}
+	}
+	return false
+}
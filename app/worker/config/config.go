@@ -14,6 +14,17 @@ type Config struct {
 	InstanceToken     string
 	HeartbeatInterval time.Duration
 
+	// 本地文件配置
+	DataRoot          string        // 数据根目录，服务端下发的文件路径都相对于它，不允许写入它之外的位置
+	StagingDir        string        // 同步时暂存文件的目录，最好与数据根目录在同一个文件系统上，以便原子替换
+	StateFile         string        // 保存本地状态（已经应用的配置、管理的文件列表等）的文件
+	OrphanGracePeriod time.Duration // 文件从清单中消失多久之后删除
+	LastGoodBundle    string        // 最近一次成功应用的配置与文件，服务端不可用时用它恢复
+
 	// 对 Caddy 控制配置
-	CaddyEndpoint string
+	CaddyEndpoint      string
+	CaddyBinary        string        // 用于获取 Caddy 版本，为空时不上报
+	DriftCheckInterval time.Duration // 检查 Caddy 正在运行的配置是否被修改的间隔， 0 表示不检查
+	SuperviseCaddy     bool          // 由 worker 启动并看管 Caddy 进程
+	CaddyStopTimeout   time.Duration // 平滑停止 Caddy 的最长等待时间，超时后强制结束
 }
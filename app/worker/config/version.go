@@ -0,0 +1,4 @@
+package config
+
+// Version 构建时通过 -ldflags "-X caddy-delivery-network/app/worker/config.Version=..." 设置
+var Version = "dev"
@@ -7,9 +7,81 @@ const (
 	TokenAuthScopes = "TokenAuth.Scopes"
 )
 
+// BundleRequest defines model for BundleRequest.
+type BundleRequest struct {
+	// ConfigDigest Digest of the config the instance already has
+	ConfigDigest *string `json:"config_digest,omitempty"`
+
+	// Files Files the instance already has
+	Files *[]FileDigest `json:"files,omitempty"`
+}
+
+// ConfigDiagnostic defines model for ConfigDiagnostic.
+type ConfigDiagnostic struct {
+	Directive *string `json:"directive,omitempty"`
+
+	// Line Line in the rendered Caddyfile
+	Line    *int   `json:"line,omitempty"`
+	Message string `json:"message"`
+
+	// Section preconfig / site, by the section markers in the rendered Caddyfile
+	Section *string `json:"section,omitempty"`
+
+	// SectionLine Line relative to the start of the section
+	SectionLine *int `json:"section_line,omitempty"`
+
+	// Severity error / warning
+	Severity string `json:"severity"`
+
+	// SiteId Site owning the line, when section is site
+	SiteId *uint `json:"site_id,omitempty"`
+}
+
+// DriftEvent The running Caddy config differed from the config the instance applied
+type DriftEvent struct {
+	// DetectedAt unix second
+	DetectedAt Timestamp `json:"detected_at"`
+
+	// Differences JSON paths that differed, e.g. `apps.http.servers.srv0.routes`
+	Differences []string `json:"differences"`
+	Error       *string  `json:"error,omitempty"`
+
+	// Reapplied Whether the applied config was loaded again
+	Reapplied bool `json:"reapplied"`
+}
+
+// FileDigest defines model for FileDigest.
+type FileDigest struct {
+	// Digest Hex encoded SHA-256 digest of the content
+	Digest Digest `json:"digest"`
+
+	// Path Path of the file relative to the data root of the instance. Instances reject absolute paths and paths
+	// escaping the data root.
+	Path FilePath `json:"path"`
+}
+
+// FileSyncResult defines model for FileSyncResult.
+type FileSyncResult struct {
+	// Digest Digest of the local file after this round
+	Digest *string `json:"digest,omitempty"`
+	Error  *string `json:"error,omitempty"`
+
+	// Path Path of the file relative to the data root of the instance. Instances reject absolute paths and paths
+	// escaping the data root.
+	Path FilePath `json:"path"`
+
+	// Status unchanged / updated / failed / rolled_back
+	Status string `json:"status"`
+}
+
 // FileUpdateRecord defines model for FileUpdateRecord.
 type FileUpdateRecord struct {
-	Path string `json:"path"`
+	// Digest Hex encoded SHA-256 digest of the content
+	Digest Digest `json:"digest"`
+
+	// Path Path of the file relative to the data root of the instance. Instances reject absolute paths and paths
+	// escaping the data root.
+	Path FilePath `json:"path"`
 
 	// UpdatedAt unix second
 	UpdatedAt Timestamp `json:"updated_at"`
@@ -17,19 +89,72 @@ type FileUpdateRecord struct {
 
 // HeartbeatRes defines model for HeartbeatRes.
 type HeartbeatRes struct {
+	// ConfigDigest Hex encoded SHA-256 digest of the content
+	ConfigDigest Digest `json:"config_digest"`
+
 	// ConfigUpdatedAt unix second
 	ConfigUpdatedAt Timestamp          `json:"config_updated_at"`
 	FilesUpdatedAt  []FileUpdateRecord `json:"files_updated_at"`
 }
 
+// SyncReport defines model for SyncReport.
+type SyncReport struct {
+	// CaddyLoadResponse Response body of the last Caddy /load request in this round
+	CaddyLoadResponse *string `json:"caddy_load_response,omitempty"`
+	CaddyVersion      *string `json:"caddy_version,omitempty"`
+
+	// ConfigDigest Digest of the config currently loaded into Caddy, empty if none
+	ConfigDigest string `json:"config_digest"`
+
+	// Diagnostics Warnings and errors from adapting the new config with Caddy /adapt
+	Diagnostics *[]ConfigDiagnostic `json:"diagnostics,omitempty"`
+
+	// Drift The running Caddy config differed from the config the instance applied
+	Drift *DriftEvent       `json:"drift,omitempty"`
+	Error *string           `json:"error,omitempty"`
+	Files *[]FileSyncResult `json:"files,omitempty"`
+
+	// Status ok / failed
+	Status string `json:"status"`
+
+	// SyncedAt unix second
+	SyncedAt      *Timestamp `json:"synced_at,omitempty"`
+	WorkerVersion *string    `json:"worker_version,omitempty"`
+}
+
+// Digest Hex encoded SHA-256 digest of the content
+type Digest = string
+
+// FilePath Path of the file relative to the data root of the instance. Instances reject absolute paths and paths
+// escaping the data root.
+type FilePath = string
+
 // Timestamp unix second
 type Timestamp = int64
 
 // Id defines model for id.
 type Id = uint
 
+// IfNoneMatch defines model for ifNoneMatch.
+type IfNoneMatch = string
+
+// GetConfigParams defines parameters for GetConfig.
+type GetConfigParams struct {
+	// IfNoneMatch Digests the instance already has, as quoted ETags
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
 // GetFilesParams defines parameters for GetFiles.
 type GetFilesParams struct {
-	// XFilePath Path of target file
+	// XFilePath Path of target file, relative to the data root of the instance
 	XFilePath *string `json:"X-File-Path,omitempty"`
+
+	// IfNoneMatch Digests the instance already has, as quoted ETags
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
 }
+
+// GetBundleJSONRequestBody defines body for GetBundle for application/json ContentType.
+type GetBundleJSONRequestBody = BundleRequest
+
+// ReportJSONRequestBody defines body for Report for application/json ContentType.
+type ReportJSONRequestBody = SyncReport
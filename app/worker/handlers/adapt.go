@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"caddy-delivery-network/app/server/gen/oapi/worker"
+	"encoding/json"
+	"fmt"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 服务端渲染配置时加在每一段前面的标记
+const (
+	configMarkerPreConfig  = "# cdn:preconfig"
+	configMarkerSitePrefix = "# cdn:site "
+)
+
+const (
+	diagnosticError   = "error"
+	diagnosticWarning = "warning"
+)
+
+// Caddyfile 适配器报错时使用的行号格式，例如 "Caddyfile:12 - Error during parsing: ..."
+var adaptErrorLine = regexp.MustCompile(`Caddyfile:(\d+)`)
+
+// adaptConfig 通过 Caddy 的 /adapt 检查配置，返回适配后的 JSON 配置以及警告与错误；配置无效时返回 error
+func (a *App) adaptConfig(config []byte) (json.RawMessage, []worker.ConfigDiagnostic, error) {
+	adaptReqUrl, err := url.JoinPath(a.cfg.CaddyEndpoint, "/adapt")
+	if err != nil {
+		return nil, nil, fmt.Errorf("fail to prepare caddy adapt url: %w", err)
+	}
+	adaptReq, err := http.NewRequest("POST", adaptReqUrl, bytes.NewReader(a.renderConfig(config)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("fail to prepare caddy adapt request: %w", err)
+	}
+	adaptReq.Header.Set("Content-Type", "text/caddyfile")
+
+	// 发送请求
+	adaptRes, err := http.DefaultClient.Do(adaptReq)
+	if err != nil {
+		a.l.Error("failed to send caddy adapt request", zap.Error(err))
+		return nil, nil, fmt.Errorf("fail to send caddy adapt request: %w", err)
+	}
+
+	defer adaptRes.Body.Close()
+
+	resBody, err := io.ReadAll(adaptRes.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fail to read caddy adapt response: %w", err)
+	}
+
+	if adaptRes.StatusCode != http.StatusOK {
+		// 适配失败，错误信息中带有行号
+		var resErr struct {
+			Error string `json:"error"`
+		}
+		if err := json.Unmarshal(resBody, &resErr); err != nil || resErr.Error == "" {
+			resErr.Error = strings.TrimSpace(string(resBody))
+		}
+
+		diagnostic := worker.ConfigDiagnostic{
+			Severity: diagnosticError,
+			Message:  resErr.Error,
+		}
+		if m := adaptErrorLine.FindStringSubmatch(resErr.Error); m != nil {
+			if line, err := strconv.Atoi(m[1]); err == nil {
+				locateDiagnostic(config, line, &diagnostic)
+			}
+		}
+		return nil, []worker.ConfigDiagnostic{diagnostic}, fmt.Errorf("caddy rejected config (%d): %s", adaptRes.StatusCode, resErr.Error)
+	}
+
+	// 适配成功，可能带有警告
+	var resOK struct {
+		Warnings []struct {
+			File      string `json:"file"`
+			Line      int    `json:"line"`
+			Directive string `json:"directive"`
+			Message   string `json:"message"`
+		} `json:"warnings"`
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(resBody, &resOK); err != nil {
+		return nil, nil, fmt.Errorf("fail to decode caddy adapt response: %w", err)
+	}
+
+	var diagnostics []worker.ConfigDiagnostic
+	for _, w := range resOK.Warnings {
+		diagnostic := worker.ConfigDiagnostic{
+			Severity: diagnosticWarning,
+			Message:  w.Message,
+		}
+		if w.Directive != "" {
+			diagnostic.Directive = &w.Directive
+		}
+		// 只有主配置文件中的行号可以对应到站点，被 import 的文件保留原样
+		if w.Line > 0 && (w.File == "" || w.File == "Caddyfile") {
+			locateDiagnostic(config, w.Line, &diagnostic)
+		} else if w.File != "" {
+			diagnostic.Message = fmt.Sprintf("%s:%d: %s", w.File, w.Line, w.Message)
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return resOK.Result, diagnostics, nil
+}
+
+// locateDiagnostic 根据配置中的段落标记，找到行号所在的段落以及在段落中的行号
+func locateDiagnostic(config []byte, line int, diagnostic *worker.ConfigDiagnostic) {
+	diagnostic.Line = &line
+
+	scanner := bufio.NewScanner(bytes.NewReader(config))
+	scanner.Buffer(nil, len(config)+1)
+	var (
+		section  string
+		siteID   *uint
+		markerAt int
+	)
+	for n := 1; n <= line && scanner.Scan(); n++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == configMarkerPreConfig {
+			section, siteID, markerAt = "preconfig", nil, n
+		} else if idStr, ok := strings.CutPrefix(text, configMarkerSitePrefix); ok {
+			if id, err := strconv.ParseUint(idStr, 10, 0); err == nil {
+				section, siteID, markerAt = "site", new(uint), n
+				*siteID = uint(id)
+			}
+		}
+	}
+	if section == "" {
+		return
+	}
+
+	sectionLine := line - markerAt
+	diagnostic.Section = &section
+	diagnostic.SiteId = siteID
+	diagnostic.SectionLine = &sectionLine
+}
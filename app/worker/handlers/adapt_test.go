@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"caddy-delivery-network/app/server/gen/oapi/worker"
+	"testing"
+)
+
+func TestLocateDiagnostic(t *testing.T) {
+	config := []byte(`{
+    admin :2019
+}
+# cdn:preconfig
+import afiles/common
+# cdn:site 3
+example.com {
+    reverse_proxy 127.0.0.1:8080
+}
+  # cdn:site 12
+www.example.com {
+    respond "ok"
+}
+# cdn:site not-a-number
+`)
+
+	tests := []struct {
+		name        string
+		line        int
+		wantSection string
+		wantSiteID  uint
+		wantLine    int
+	}{
+		{"before any marker", 2, "", 0, 0},
+		{"preconfig", 5, "preconfig", 0, 1},
+		{"on the marker line", 6, "site", 3, 0},
+		{"first site", 8, "site", 3, 2},
+		{"indented marker", 12, "site", 12, 2},
+		{"invalid marker is ignored", 15, "site", 12, 5},
+		{"beyond the end", 100, "site", 12, 90},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diagnostic worker.ConfigDiagnostic
+			locateDiagnostic(config, tt.line, &diagnostic)
+
+			if diagnostic.Line == nil || *diagnostic.Line != tt.line {
+				t.Fatalf("unexpected line %v", diagnostic.Line)
+			}
+			if tt.wantSection == "" {
+				if diagnostic.Section != nil || diagnostic.SiteId != nil || diagnostic.SectionLine != nil {
+					t.Fatalf("expected no section, got %+v", diagnostic)
+				}
+				return
+			}
+			if diagnostic.Section == nil || *diagnostic.Section != tt.wantSection {
+				t.Fatalf("unexpected section %v", diagnostic.Section)
+			}
+			if tt.wantSection == "site" {
+				if diagnostic.SiteId == nil || *diagnostic.SiteId != tt.wantSiteID {
+					t.Fatalf("unexpected site id %v", diagnostic.SiteId)
+				}
+			} else if diagnostic.SiteId != nil {
+				t.Fatalf("unexpected site id %v", *diagnostic.SiteId)
+			}
+			if diagnostic.SectionLine == nil || *diagnostic.SectionLine != tt.wantLine {
+				t.Fatalf("unexpected section line %v", diagnostic.SectionLine)
+			}
+		})
+	}
+}
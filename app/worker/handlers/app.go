@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"caddy-delivery-network/app/worker/config"
+	"context"
 	"go.uber.org/zap"
 	"sync"
 	"time"
@@ -11,38 +12,103 @@ type App struct {
 	cfg *config.Config
 	l   *zap.Logger
 
-	lastConfigUpdate int64
+	lastConfig       []byte // 已经加载到 Caddy 的配置，新配置被拒绝时用来恢复
+	lastConfigDigest string // 已经加载到 Caddy 的配置的摘要
+	expectedConfig   []byte // 已经加载的配置适配后规范化的 JSON ，用于检查配置漂移
+	reported         bool   // 启动后是否已经上报过同步结果
+	state            *workerState
+	lastGoodSaved    bool // 启动后是否已经保存过本地同步包
 	ticker           *time.Ticker
+	driftTicker      *time.Ticker
 	stopChan         chan struct{}
+	syncChan         chan struct{} // 收到服务端通知时立即同步
+	cancelEvents     context.CancelFunc
 	lock             *sync.Mutex
+	caddy            *caddyProcess // 由 worker 看管的 Caddy 进程，没有启用时为 nil
 }
 
 func NewApp(cfg *config.Config, l *zap.Logger) *App {
 	return &App{
-		cfg: cfg,
-		l:   l,
+		cfg:  cfg,
+		l:    l,
+		lock: &sync.Mutex{},
 	}
 }
 
 func (a *App) Start() {
 	a.ticker = time.NewTicker(a.cfg.HeartbeatInterval)
+	if a.cfg.DriftCheckInterval > 0 {
+		a.driftTicker = time.NewTicker(a.cfg.DriftCheckInterval)
+	}
 	a.stopChan = make(chan struct{})
+	a.syncChan = make(chan struct{}, 1)
+	a.cleanStaging()
+	a.loadState()
+
+	// 由 worker 启动并看管 Caddy
+	if a.cfg.SuperviseCaddy {
+		if err := a.startCaddy(); err != nil {
+			a.l.Error("failed to start caddy", zap.Error(err))
+		}
+	}
+
+	// 订阅服务端的变化通知，断开时仍然依靠心跳轮询
+	var eventsCtx context.Context
+	eventsCtx, a.cancelEvents = context.WithCancel(context.Background())
+	go a.listenEvents(eventsCtx)
+
 	go a.loop()
 }
 
 func (a *App) loop() {
-	select {
-	case <-a.ticker.C:
-		a.l.Debug("heartbeat loop")
+	// 启动时先同步一次，不用等到第一个心跳间隔；看管 Caddy 时等它就绪后再同步
+	if a.caddy == nil {
+		// Caddy 不由 worker 启动，不能确定它正在运行恢复的配置
+		a.checkRestoredConfig()
 		a.heartbeat()
-	case <-a.stopChan:
-		a.l.Debug("stop heartbeat loop")
-		close(a.stopChan)
-		break
+	}
+
+	// 没有启用漂移检查时 driftC 为 nil ，永远不会触发
+	var driftC <-chan time.Time
+	if a.driftTicker != nil {
+		driftC = a.driftTicker.C
+	}
+
+	for {
+		select {
+		case <-a.ticker.C:
+			a.l.Debug("heartbeat loop")
+			a.heartbeat()
+		case <-driftC:
+			a.l.Debug("drift check")
+			a.checkDrift()
+		case <-a.syncChan:
+			a.l.Debug("sync requested by server")
+			a.heartbeat()
+		case <-a.stopChan:
+			a.l.Debug("stop heartbeat loop")
+			return
+		}
+	}
+}
+
+// requestSync 请求立即同步，已经有等待处理的请求时合并
+func (a *App) requestSync() {
+	select {
+	case a.syncChan <- struct{}{}:
+	default:
 	}
 }
 
 func (a *App) Stop() {
 	a.ticker.Stop()
-	a.stopChan <- struct{}{}
+	if a.driftTicker != nil {
+		a.driftTicker.Stop()
+	}
+	a.cancelEvents()
+	close(a.stopChan)
+
+	if a.caddy != nil {
+		a.stopCaddy()
+	}
 }
@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"bytes"
+	"caddy-delivery-network/app/server/gen/oapi/worker"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// 服务端渲染配置时使用的数据根目录占位符
+const dataRootPlaceholder = "{$CDN_DATA_ROOT}"
+
+// dataPath 把服务端下发的相对路径转换为数据根目录下的路径，拒绝绝对路径和跳出根目录的路径，
+// 以及以 . 开头的保留路径（ worker 自己的状态、暂存目录与本地同步包默认放在这里）
+func dataPath(root string, relPath string) (string, error) {
+	if !filepath.IsLocal(relPath) {
+		return "", fmt.Errorf("path %q is not inside data root", relPath)
+	}
+	if isReservedPath(relPath) {
+		return "", fmt.Errorf("path %q is reserved for worker", relPath)
+	}
+	return filepath.Join(root, relPath), nil
+}
+
+// isReservedPath 相对路径的第一段以 . 开头时保留给 worker 使用
+func isReservedPath(relPath string) bool {
+	first, _, _ := strings.Cut(filepath.ToSlash(filepath.Clean(relPath)), "/")
+	return strings.HasPrefix(first, ".")
+}
+
+// renderConfig 把服务端下发的配置中的占位符替换为数据根目录，只在发送给 Caddy 时替换，保存的仍然是原始内容
+func (a *App) renderConfig(config []byte) []byte {
+	return bytes.ReplaceAll(config, []byte(dataRootPlaceholder), []byte(a.cfg.DataRoot))
+}
+
+type stagedFile struct {
+	path    string // 目标路径
+	staged  string // 暂存路径
+	backup  string // 原文件的备份路径，原文件不存在时为空
+	swapped bool   // 是否已经替换（或尝试替换）到目标路径
+}
+
+// syncPlan 一轮同步中需要应用的所有变更
+type syncPlan struct {
+	root         string
+	dir          string
+	files        []*stagedFile
+	config       []byte
+	configDigest string
+	diagnostics  []worker.ConfigDiagnostic // 检查配置时 Caddy 给出的警告与错误
+}
+
+func (a *App) newSyncPlan() (*syncPlan, error) {
+	if err := os.MkdirAll(a.cfg.StagingDir, 0700); err != nil {
+		return nil, fmt.Errorf("fail to create staging directory: %w", err)
+	}
+	dir, err := os.MkdirTemp(a.cfg.StagingDir, "sync-")
+	if err != nil {
+		return nil, fmt.Errorf("fail to create staging directory: %w", err)
+	}
+
+	return &syncPlan{root: a.cfg.DataRoot, dir: dir}, nil
+}
+
+// cleanStaging 清理上次异常退出时留下的暂存目录
+func (a *App) cleanStaging() {
+	entries, err := filepath.Glob(filepath.Join(a.cfg.StagingDir, "sync-*"))
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		a.l.Warn("removing leftover staging directory", zap.String("path", entry))
+		if err := os.RemoveAll(entry); err != nil {
+			a.l.Error("failed to remove leftover staging directory", zap.String("path", entry), zap.Error(err))
+		}
+	}
+}
+
+func (p *syncPlan) cleanup(l *zap.Logger) {
+	if err := os.RemoveAll(p.dir); err != nil {
+		l.Error("failed to remove staging directory", zap.String("path", p.dir), zap.Error(err))
+	}
+}
+
+func (p *syncPlan) isEmpty() bool {
+	return len(p.files) == 0 && p.config == nil
+}
+
+// stageFile 暂存文件， relPath 是服务端下发的相对路径
+func (p *syncPlan) stageFile(relPath string, content []byte) error {
+	path, err := dataPath(p.root, relPath)
+	if err != nil {
+		return err
+	}
+
+	// 私钥只允许所有者读取，替换时会保留暂存文件的权限
+	mode := os.FileMode(0644)
+	if isPrivateKey(relPath) {
+		mode = 0600
+	}
+	staged := filepath.Join(p.dir, strconv.Itoa(len(p.files)))
+	if err := writeFileSync(staged, content, mode); err != nil {
+		return err
+	}
+
+	p.files = append(p.files, &stagedFile{path: path, staged: staged})
+	return nil
+}
+
+func (p *syncPlan) stageConfig(config []byte, digest string) {
+	p.config = config
+	p.configDigest = digest
+}
+
+// swap 备份原文件，再把暂存的文件替换到目标路径
+func (p *syncPlan) swap() error {
+	for i, f := range p.files {
+		if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+			return fmt.Errorf("fail to create parent directory of %s: %w", f.path, err)
+		}
+
+		// 复制而非移动，替换完成之前目标路径始终有完整的文件
+		backup := filepath.Join(p.dir, strconv.Itoa(i)+".bak")
+		if err := copyFile(f.path, backup); err == nil {
+			f.backup = backup
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("fail to backup %s: %w", f.path, err)
+		}
+
+		f.swapped = true
+		if err := moveFile(f.staged, f.path); err != nil {
+			return fmt.Errorf("fail to replace %s: %w", f.path, err)
+		}
+	}
+
+	return nil
+}
+
+// restore 把已经替换的文件恢复成原来的版本，原来不存在的文件会被删除
+func (p *syncPlan) restore(l *zap.Logger) {
+	for i := len(p.files) - 1; i >= 0; i-- {
+		f := p.files[i]
+		if !f.swapped {
+			continue
+		}
+
+		if f.backup == "" {
+			if err := os.Remove(f.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				l.Error("failed to remove new file", zap.String("path", f.path), zap.Error(err))
+			}
+		} else if err := moveFile(f.backup, f.path); err != nil {
+			l.Error("failed to restore file", zap.String("path", f.path), zap.Error(err))
+		}
+	}
+}
+
+// applySyncPlan 替换文件并加载配置，返回 Caddy 的响应内容；配置无效或 Caddy 拒绝时恢复之前的文件和配置
+func (a *App) applySyncPlan(p *syncPlan) (string, error) {
+	// 先替换文件， Caddy 适配配置时会从磁盘读取 import 的文件
+	if err := p.swap(); err != nil {
+		p.restore(a.l)
+		return "", fmt.Errorf("fail to swap files: %w", err)
+	}
+
+	// 只有文件变化时也要重新加载，让 Caddy 读取新的证书
+	config, force := p.config, false
+	if config == nil {
+		config, force = a.lastConfig, true
+	}
+	if config == nil {
+		// 还没有加载过配置，等配置同步时一起加载
+		return "", nil
+	}
+
+	// 检查配置，无效时恢复原来的文件；适配结果同时作为检查配置漂移的依据（ import 的文件可能发生了变化）
+	adapted, diagnostics, err := a.adaptConfig(config)
+	p.diagnostics = diagnostics
+	for _, d := range diagnostics {
+		a.l.Warn("config diagnostic", zap.String("severity", d.Severity), zap.String("message", d.Message), zap.Uintp("siteID", d.SiteId), zap.Intp("line", d.Line))
+	}
+	if err == nil {
+		adapted, err = a.pinAdminListen(adapted)
+	}
+	if err != nil {
+		p.restore(a.l)
+		return "", fmt.Errorf("invalid config: %w", err)
+	}
+
+	loadResponse, err := a.loadConfig(config, force)
+	if err != nil {
+		p.restore(a.l)
+
+		// Caddy 加载失败时会继续使用原来的配置，但可能已经读取了新的文件，重新加载一次原来的版本
+		if len(p.files) > 0 && a.lastConfig != nil {
+			if _, err := a.loadConfig(a.lastConfig, true); err != nil {
+				a.l.Error("failed to reload last known good config", zap.Error(err))
+			}
+		}
+
+		return loadResponse, fmt.Errorf("fail to load config: %w", err)
+	}
+
+	if p.config != nil {
+		a.lastConfig = p.config
+		a.lastConfigDigest = p.configDigest
+	}
+	a.setExpectedConfig(adapted)
+
+	a.l.Info("changes applied", zap.Int("files", len(p.files)), zap.Bool("config", p.config != nil))
+	return loadResponse, nil
+}
+
+// writeFileSync 写入文件并落盘，文件已经存在时同样修改为指定的权限
+func writeFileSync(path string, content []byte, mode os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	if err := f.Chmod(mode); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// copyFile 复制文件，保留原文件的权限
+func copyFile(src string, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	content, err := io.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	return writeFileSync(dst, content, info.Mode().Perm())
+}
+
+// moveFile 原子地把 src 移动到 dst ，跨文件系统时先复制到 dst 所在的目录再重命名
+func moveFile(src string, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	tmp := dst + ".cdn-tmp"
+	if err := copyFile(src, tmp); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Remove(src)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDataPath(t *testing.T) {
+	root := filepath.FromSlash("/var/lib/cdn")
+
+	tests := []struct {
+		name    string
+		relPath string
+		want    string
+		wantErr bool
+	}{
+		{"file", "afiles/site.conf", "/var/lib/cdn/afiles/site.conf", false},
+		{"cert", "certs/1/key.pem", "/var/lib/cdn/certs/1/key.pem", false},
+		{"inner dot dot", "certs/1/../2/cert.pem", "/var/lib/cdn/certs/2/cert.pem", false},
+		{"absolute", "/etc/passwd", "", true},
+		{"dot dot", "..", "", true},
+		{"leading dot dot", "../cdn-other/key.pem", "", true},
+		{"escape through inner dot dot", "a/../../b", "", true},
+		{"empty", "", "", true},
+		{"state file", ".worker-state.json", "", true},
+		{"last good bundle", ".last-good.tar.gz", "", true},
+		{"staging", ".staging/sync-1/0", "", true},
+		{"staging through inner dot dot", "certs/../.staging/x", "", true},
+		{"dot file below first level", "afiles/.htaccess", "/var/lib/cdn/afiles/.htaccess", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dataPath(root, filepath.FromSlash(tt.relPath))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("dataPath(%q) error = %v, wantErr %v", tt.relPath, err, tt.wantErr)
+			}
+			if err == nil && got != filepath.FromSlash(tt.want) {
+				t.Fatalf("dataPath(%q) = %q, want %q", tt.relPath, got, tt.want)
+			}
+		})
+	}
+}
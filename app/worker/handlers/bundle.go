@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"archive/tar"
+	"bytes"
+	"caddy-delivery-network/app/server/gen/oapi/worker"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// 同步包中的文件名，与服务端保持一致
+const (
+	bundleManifestName = "manifest.json"
+	bundleConfigName   = "Caddyfile"
+)
+
+type bundle struct {
+	manifest worker.BundleManifest
+	config   []byte
+	entries  map[string][]byte
+}
+
+// downloadBundle 一次请求下载配置与所有需要更新的文件，只包含摘要与本地不同的内容
+func (a *App) downloadBundle(local map[string]string) (*bundle, error) {
+	// 准备请求体，告诉服务端本地已经有的内容
+	reqBody := worker.BundleRequest{
+		Files: &[]worker.FileDigest{},
+	}
+	if a.lastConfigDigest != "" {
+		reqBody.ConfigDigest = &a.lastConfigDigest
+	}
+	for fPath, digest := range local {
+		*reqBody.Files = append(*reqBody.Files, worker.FileDigest{
+			Path:   fPath,
+			Digest: digest,
+		})
+	}
+	reqBytes, err := json.Marshal(&reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("fail to marshal bundle request: %w", err)
+	}
+
+	bundlePath := fmt.Sprintf("/api/worker/%d/bundle", a.cfg.InstanceID)
+	bundleReqUrl, err := url.JoinPath(a.cfg.ServerEndpoint, bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("fail to join bundle request url: %w", err)
+	}
+	bundleReq, err := http.NewRequest("POST", bundleReqUrl, bytes.NewReader(reqBytes))
+	if err != nil {
+		return nil, fmt.Errorf("fail to prepare bundle request: %w", err)
+	}
+	bundleReq.Header.Set("Authorization", "Bearer "+a.cfg.InstanceToken)
+	bundleReq.Header.Set("Content-Type", "application/json")
+
+	// 发送请求
+	bundleRes, err := http.DefaultClient.Do(bundleReq)
+	if err != nil {
+		return nil, fmt.Errorf("fail to send bundle request: %w", err)
+	}
+
+	defer bundleRes.Body.Close()
+
+	if bundleRes.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected bundle response status: %d", bundleRes.StatusCode)
+	}
+
+	// 读取并校验
+	b, err := readBundle(bundleRes.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.verify(); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func readBundle(r io.Reader) (*bundle, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("fail to open bundle: %w", err)
+	}
+	defer gr.Close()
+
+	b := &bundle{
+		entries: make(map[string][]byte),
+	}
+	hasManifest := false
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("fail to read bundle: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("fail to read bundle entry %s: %w", header.Name, err)
+		}
+
+		switch header.Name {
+		case bundleManifestName:
+			if err := json.Unmarshal(content, &b.manifest); err != nil {
+				return nil, fmt.Errorf("fail to decode bundle manifest: %w", err)
+			}
+			hasManifest = true
+		case bundleConfigName:
+			b.config = content
+		default:
+			b.entries[header.Name] = content
+		}
+	}
+	if !hasManifest {
+		return nil, fmt.Errorf("bundle has no manifest")
+	}
+
+	return b, nil
+}
+
+// verify 检查包里的内容是否完整，并与清单中的摘要一致
+func (b *bundle) verify() error {
+	if b.manifest.ConfigIncluded {
+		if b.config == nil {
+			return fmt.Errorf("bundle has no config")
+		}
+		if _, err := checkDigest(b.config, b.manifest.ConfigDigest); err != nil {
+			return fmt.Errorf("fail to verify config: %w", err)
+		}
+	}
+	for _, f := range b.manifest.Files {
+		if f.Entry == nil || *f.Entry == "" {
+			continue
+		}
+		content, ok := b.entries[*f.Entry]
+		if !ok {
+			return fmt.Errorf("bundle has no entry %s for %s", *f.Entry, f.Path)
+		}
+		if _, err := checkDigest(content, f.Digest); err != nil {
+			return fmt.Errorf("fail to verify %s: %w", f.Path, err)
+		}
+	}
+	return nil
+}
+
+// stageBundle 把包里的内容放进暂存目录，返回暂存的文件在 result.files 中的位置
+func (a *App) stageBundle(plan *syncPlan, b *bundle, local map[string]string, result *syncResult) ([]int, error) {
+	var staged []int
+	for _, f := range b.manifest.Files {
+		if f.Entry == nil || *f.Entry == "" {
+			if local[f.Path] != f.Digest {
+				err := fmt.Errorf("bundle left out outdated file")
+				a.l.Error("failed to sync file", zap.String("path", f.Path), zap.Error(err))
+				result.files = append(result.files, fileSyncResult(f.Path, local[f.Path], fileSyncFailed, err))
+				return nil, err
+			}
+			result.files = append(result.files, fileSyncResult(f.Path, f.Digest, fileSyncUnchanged, nil))
+			continue
+		}
+
+		if err := plan.stageFile(f.Path, b.entries[*f.Entry]); err != nil {
+			a.l.Error("failed to stage file", zap.String("path", f.Path), zap.Error(err))
+			result.files = append(result.files, fileSyncResult(f.Path, local[f.Path], fileSyncFailed, err))
+			return nil, err
+		}
+		staged = append(staged, len(result.files))
+		result.files = append(result.files, fileSyncResult(f.Path, f.Digest, fileSyncUpdated, nil))
+	}
+
+	if b.manifest.ConfigIncluded && b.manifest.ConfigDigest != a.lastConfigDigest {
+		plan.stageConfig(b.config, b.manifest.ConfigDigest)
+	}
+
+	return staged, nil
+}
@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	caddyRestartMinDelay = 1 * time.Second
+	caddyRestartMaxDelay = 1 * time.Minute
+	caddyStableAfter     = 1 * time.Minute  // 运行超过这个时间后退出，重新开始退避
+	caddyReadyTimeout    = 30 * time.Second // 等待管理接口可用的最长时间
+)
+
+// caddyProcess 由 worker 启动并看管的 Caddy 进程
+type caddyProcess struct {
+	mu         sync.Mutex
+	cmd        *exec.Cmd
+	exited     chan struct{} // 当前进程退出时关闭
+	stopping   bool          // 正在停止，进程退出后不再重启
+	restarting bool          // 正在重启（例如升级），进程退出后立即启动
+	wake       chan struct{}
+	done       chan struct{}
+}
+
+// startCaddy 写入引导配置并启动看管循环
+func (a *App) startCaddy() error {
+	bootstrap, err := a.caddyBootstrapConfig()
+	if err != nil {
+		return err
+	}
+	bootstrapPath := filepath.Join(a.cfg.DataRoot, ".caddy-bootstrap.json")
+	if err := os.MkdirAll(a.cfg.DataRoot, 0755); err != nil {
+		return fmt.Errorf("fail to create data root: %w", err)
+	}
+	if err := writeFileSync(bootstrapPath, bootstrap, 0644); err != nil {
+		return fmt.Errorf("fail to write caddy bootstrap config: %w", err)
+	}
+
+	a.caddy = &caddyProcess{
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	go a.superviseCaddy(bootstrapPath)
+	return nil
+}
+
+// caddyBootstrapConfig 引导配置只让管理接口监听在 CaddyEndpoint 上，站点配置随后由同步加载
+func (a *App) caddyBootstrapConfig() ([]byte, error) {
+	endpoint, err := url.Parse(a.cfg.CaddyEndpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid caddy endpoint %q", a.cfg.CaddyEndpoint)
+	}
+
+	return json.Marshal(map[string]any{
+		"admin": map[string]any{
+			"listen": endpoint.Host,
+		},
+	})
+}
+
+// pinAdminListen 看管 Caddy 时让配置中的管理接口始终监听在 CaddyEndpoint 上，
+// 否则没有设置 admin 全局选项的配置会把管理接口移回默认地址， worker 就失去了对 Caddy 的控制
+func (a *App) pinAdminListen(adapted json.RawMessage) (json.RawMessage, error) {
+	if a.caddy == nil {
+		return adapted, nil
+	}
+
+	endpoint, err := url.Parse(a.cfg.CaddyEndpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid caddy endpoint %q", a.cfg.CaddyEndpoint)
+	}
+
+	var config map[string]json.RawMessage
+	if err := json.Unmarshal(adapted, &config); err != nil {
+		return nil, fmt.Errorf("fail to decode adapted config: %w", err)
+	}
+	if config == nil {
+		config = make(map[string]json.RawMessage)
+	}
+	admin := make(map[string]json.RawMessage)
+	if raw, ok := config["admin"]; ok {
+		if err := json.Unmarshal(raw, &admin); err != nil {
+			return nil, fmt.Errorf("fail to decode admin config: %w", err)
+		}
+		if admin == nil {
+			admin = make(map[string]json.RawMessage)
+		}
+	}
+	delete(admin, "disabled")
+	admin["listen"], _ = json.Marshal(endpoint.Host)
+	config["admin"], _ = json.Marshal(admin)
+
+	return json.Marshal(config)
+}
+
+func (a *App) superviseCaddy(bootstrapPath string) {
+	defer close(a.caddy.done)
+
+	delay := caddyRestartMinDelay
+	for {
+		startedAt := time.Now()
+		cmd, err := a.launchCaddy(bootstrapPath)
+		if err == nil {
+			a.l.Info("caddy started", zap.Int("pid", cmd.Process.Pid))
+			go a.onCaddyStarted()
+			err = cmd.Wait()
+		}
+
+		a.caddy.mu.Lock()
+		if a.caddy.exited != nil {
+			close(a.caddy.exited)
+			a.caddy.exited = nil
+		}
+		a.caddy.cmd = nil
+		stopping, restarting := a.caddy.stopping, a.caddy.restarting
+		a.caddy.restarting = false
+		a.caddy.mu.Unlock()
+
+		if stopping {
+			a.l.Info("caddy stopped", zap.Error(err))
+			return
+		}
+		if restarting {
+			a.l.Info("caddy exited for restart", zap.Error(err))
+			delay = caddyRestartMinDelay
+			continue
+		}
+
+		// 意外退出，按照退避间隔重启；运行了足够久的话重新开始退避
+		if time.Since(startedAt) > caddyStableAfter {
+			delay = caddyRestartMinDelay
+		}
+		a.l.Error("caddy exited unexpectedly, restarting", zap.Error(err), zap.Duration("delay", delay))
+		select {
+		case <-time.After(delay):
+		case <-a.caddy.wake:
+			// 等待期间被要求停止或重启
+		}
+		if delay = delay * 2; delay > caddyRestartMaxDelay {
+			delay = caddyRestartMaxDelay
+		}
+
+		a.caddy.mu.Lock()
+		stopping = a.caddy.stopping
+		a.caddy.restarting = false
+		a.caddy.mu.Unlock()
+		if stopping {
+			return
+		}
+	}
+}
+
+func (a *App) launchCaddy(bootstrapPath string) (*exec.Cmd, error) {
+	cmd := exec.Command(a.cfg.CaddyBinary, "run", "--config", bootstrapPath)
+	// 让配置中的占位符在 Caddy 直接读取时也能使用
+	cmd.Env = append(os.Environ(), "CDN_DATA_ROOT="+a.cfg.DataRoot)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	a.caddy.mu.Lock()
+	defer a.caddy.mu.Unlock()
+	if a.caddy.stopping {
+		return nil, fmt.Errorf("stopping")
+	}
+	if err := cmd.Start(); err != nil {
+		a.l.Error("failed to start caddy", zap.String("binary", a.cfg.CaddyBinary), zap.Error(err))
+		return nil, err
+	}
+	a.caddy.cmd = cmd
+	a.caddy.exited = make(chan struct{})
+
+	l := a.l.Named("caddy")
+	go pipeCaddyLog(l, stdout)
+	go pipeCaddyLog(l, stderr)
+
+	return cmd, nil
+}
+
+// pipeCaddyLog 把 Caddy 输出的日志转到 zap ， Caddy 的 JSON 日志会保留级别和字段
+func pipeCaddyLog(l *zap.Logger, r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+
+		var entry map[string]any
+		if err := json.Unmarshal(line, &entry); err != nil {
+			l.Info(string(line))
+			continue
+		}
+
+		level := zapcore.InfoLevel
+		if lvl, ok := entry["level"].(string); ok {
+			if err := level.UnmarshalText([]byte(lvl)); err != nil {
+				level = zapcore.InfoLevel
+			}
+		}
+		msg, _ := entry["msg"].(string)
+		fields := []zap.Field{}
+		for k, v := range entry {
+			switch k {
+			case "level", "msg", "ts":
+				continue
+			}
+			fields = append(fields, zap.Any(k, v))
+		}
+		if ce := l.Check(level, msg); ce != nil {
+			ce.Write(fields...)
+		}
+	}
+}
+
+// onCaddyStarted 等待管理接口可用后重新加载已经应用的配置，新进程只有引导配置
+func (a *App) onCaddyStarted() {
+	configUrl, err := url.JoinPath(a.cfg.CaddyEndpoint, "/config/")
+	if err != nil {
+		return
+	}
+	deadline := time.Now().Add(caddyReadyTimeout)
+	for {
+		res, err := http.Get(configUrl)
+		if err == nil {
+			res.Body.Close()
+			if res.StatusCode == http.StatusOK {
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			a.l.Error("caddy admin endpoint not ready", zap.Error(err))
+			return
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	// 新进程只有引导配置，先恢复已经应用的配置（服务端不可用时也能提供服务），再同步一次
+	defer a.requestSync()
+	if a.lastConfig == nil {
+		if _, err := os.Stat(a.cfg.LastGoodBundle); err != nil {
+			return
+		}
+	}
+	if _, err := a.reapply(); err != nil {
+		a.l.Error("failed to reapply config after caddy start", zap.Error(err))
+		return
+	}
+	a.l.Info("config reapplied after caddy start")
+}
+
+// signalCaddy 给正在运行的 Caddy 发送退出信号，超时后强制结束
+func (a *App) signalCaddy(cmd *exec.Cmd, exited <-chan struct{}) {
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		a.l.Warn("failed to signal caddy, killing", zap.Error(err))
+		cmd.Process.Kill()
+		return
+	}
+
+	select {
+	case <-exited:
+	case <-time.After(a.cfg.CaddyStopTimeout):
+		a.l.Warn("caddy did not stop in time, killing", zap.Duration("timeout", a.cfg.CaddyStopTimeout))
+		cmd.Process.Kill()
+	}
+}
+
+// RestartCaddy 平滑地重启 Caddy ，用于升级可执行文件；没有看管 Caddy 时不做任何事
+func (a *App) RestartCaddy() {
+	if a.caddy == nil {
+		a.l.Warn("caddy is not supervised by worker, ignoring restart")
+		return
+	}
+
+	a.caddy.mu.Lock()
+	cmd, exited := a.caddy.cmd, a.caddy.exited
+	a.caddy.restarting = true
+	a.caddy.mu.Unlock()
+
+	if cmd == nil {
+		// 正在等待重启，直接唤醒
+		select {
+		case a.caddy.wake <- struct{}{}:
+		default:
+		}
+		return
+	}
+
+	a.l.Info("restarting caddy")
+	a.signalCaddy(cmd, exited)
+}
+
+// stopCaddy 平滑地停止 Caddy 并等待看管循环结束
+func (a *App) stopCaddy() {
+	a.caddy.mu.Lock()
+	a.caddy.stopping = true
+	cmd, exited := a.caddy.cmd, a.caddy.exited
+	a.caddy.mu.Unlock()
+
+	select {
+	case a.caddy.wake <- struct{}{}:
+	default:
+	}
+	if cmd != nil {
+		a.signalCaddy(cmd, exited)
+	}
+	<-a.caddy.done
+}
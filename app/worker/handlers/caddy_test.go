@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"caddy-delivery-network/app/worker/config"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestPinAdminListen(t *testing.T) {
+	a := NewApp(&config.Config{
+		CaddyEndpoint: "http://127.0.0.1:2999",
+	}, zap.NewNop())
+
+	tests := []struct {
+		name    string
+		adapted string
+		want    string
+		wantErr bool
+	}{
+		{"no admin", `{"apps":{}}`, `{"admin":{"listen":"127.0.0.1:2999"},"apps":{}}`, false},
+		{"other listen", `{"admin":{"listen":"localhost:2019"}}`, `{"admin":{"listen":"127.0.0.1:2999"}}`, false},
+		{"keeps other options", `{"admin":{"enforce_origin":true}}`, `{"admin":{"enforce_origin":true,"listen":"127.0.0.1:2999"}}`, false},
+		{"disabled", `{"admin":{"disabled":true}}`, `{"admin":{"listen":"127.0.0.1:2999"}}`, false},
+		{"null", `null`, `{"admin":{"listen":"127.0.0.1:2999"}}`, false},
+		{"invalid", `[]`, "", true},
+	}
+
+	// 没有看管 Caddy 时不修改
+	if got, err := a.pinAdminListen([]byte(`{"apps":{}}`)); err != nil || string(got) != `{"apps":{}}` {
+		t.Fatalf("pinAdminListen() without supervision = %s, %v", got, err)
+	}
+
+	a.caddy = &caddyProcess{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := a.pinAdminListen([]byte(tt.adapted))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("pinAdminListen(%s) error = %v, wantErr %v", tt.adapted, err, tt.wantErr)
+			}
+			if err == nil && string(got) != tt.want {
+				t.Fatalf("pinAdminListen(%s) = %s, want %s", tt.adapted, got, tt.want)
+			}
+		})
+	}
+}
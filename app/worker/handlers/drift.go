@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"bytes"
+	"caddy-delivery-network/app/server/gen/oapi/worker"
+	"encoding/json"
+	"fmt"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	driftMaxDepth       = 5  // 比较到这一层为止，更深的差异记在这一层上
+	driftMaxDifferences = 20 // 最多记录的差异数量
+)
+
+// setExpectedConfig 记录已经加载的配置适配后的 JSON ，用于检查正在运行的配置是否被修改
+func (a *App) setExpectedConfig(adapted json.RawMessage) {
+	normalized, err := normalizeJSON(adapted)
+	if err != nil {
+		a.l.Error("failed to normalize adapted config, drift detection disabled until next load", zap.Error(err))
+		a.expectedConfig = nil
+		return
+	}
+	a.expectedConfig = normalized
+}
+
+// checkDrift 比较 Caddy 正在运行的配置与已经加载的配置，不同时重新加载并上报
+func (a *App) checkDrift() {
+	if !a.lock.TryLock() {
+		// 正在同步，跳过这一轮
+		return
+	}
+	defer a.lock.Unlock()
+
+	if a.expectedConfig == nil || a.lastConfig == nil {
+		// 还没有加载过配置
+		return
+	}
+
+	running, err := a.runningConfig()
+	if err != nil {
+		a.l.Error("failed to get running caddy config", zap.Error(err))
+		return
+	}
+	if bytes.Equal(running, a.expectedConfig) {
+		return
+	}
+
+	// Caddy 重启后只有空配置，不需要联系服务端，直接从本地同步包恢复
+	if isEmptyCaddyConfig(running) {
+		a.l.Warn("caddy has no config (restarted?), reapplying")
+		result := &syncResult{drift: &worker.DriftEvent{
+			DetectedAt:  time.Now().Unix(),
+			Differences: []string{"(root): empty config"},
+		}}
+		if result.loadResponse, err = a.reapply(); err != nil {
+			a.l.Error("failed to reapply config", zap.Error(err))
+			errMsg := err.Error()
+			result.drift.Error = &errMsg
+			result.err = err
+		} else {
+			result.drift.Reapplied = true
+		}
+		if err := a.sendReport(result); err != nil {
+			a.l.Error("failed to send report", zap.Error(err))
+		}
+		return
+	}
+
+	// 找出不同的地方
+	var expectedValue, runningValue any
+	_ = json.Unmarshal(a.expectedConfig, &expectedValue)
+	_ = json.Unmarshal(running, &runningValue)
+	var differences []string
+	diffJSON("", expectedValue, runningValue, 0, &differences)
+	a.l.Warn("running caddy config drifted, reapplying", zap.Strings("differences", differences))
+
+	// 重新加载已经应用的配置
+	drift := &worker.DriftEvent{
+		DetectedAt:  time.Now().Unix(),
+		Differences: differences,
+	}
+	result := &syncResult{drift: drift}
+	if result.loadResponse, err = a.loadConfig(a.lastConfig, true); err != nil {
+		a.l.Error("failed to reapply config", zap.Error(err))
+		errMsg := err.Error()
+		drift.Error = &errMsg
+		result.err = err
+	} else {
+		drift.Reapplied = true
+	}
+
+	if err := a.sendReport(result); err != nil {
+		a.l.Error("failed to send report", zap.Error(err))
+	}
+}
+
+// runningConfig 获取 Caddy 正在运行的配置，返回规范化的 JSON
+func (a *App) runningConfig() ([]byte, error) {
+	configReqUrl, err := url.JoinPath(a.cfg.CaddyEndpoint, "/config/")
+	if err != nil {
+		return nil, fmt.Errorf("fail to prepare caddy config url: %w", err)
+	}
+	configRes, err := http.Get(configReqUrl)
+	if err != nil {
+		return nil, fmt.Errorf("fail to send caddy config request: %w", err)
+	}
+
+	defer configRes.Body.Close()
+
+	resBody, err := io.ReadAll(configRes.Body)
+	if err != nil {
+		return nil, fmt.Errorf("fail to read caddy config response: %w", err)
+	}
+	if configRes.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected caddy config response status: %d", configRes.StatusCode)
+	}
+
+	return normalizeJSON(resBody)
+}
+
+// normalizeJSON 重新编码 JSON ，对象的键按顺序排列，便于直接比较
+func normalizeJSON(content []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// diffJSON 记录两个 JSON 值中不同的路径
+func diffJSON(path string, expected any, running any, depth int, out *[]string) {
+	if len(*out) >= driftMaxDifferences || reflect.DeepEqual(expected, running) {
+		return
+	}
+
+	label := path
+	if label == "" {
+		label = "(root)"
+	}
+	if depth >= driftMaxDepth {
+		*out = append(*out, label)
+		return
+	}
+
+	switch e := expected.(type) {
+	case map[string]any:
+		r, ok := running.(map[string]any)
+		if !ok {
+			*out = append(*out, label+": type changed")
+			return
+		}
+		keys := make([]string, 0, len(e)+len(r))
+		for k := range e {
+			keys = append(keys, k)
+		}
+		for k := range r {
+			if _, ok := e[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := k
+			if path != "" {
+				child = path + "." + k
+			}
+			ev, eok := e[k]
+			rv, rok := r[k]
+			switch {
+			case !rok:
+				*out = append(*out, child+": missing")
+			case !eok:
+				*out = append(*out, child+": added")
+			default:
+				diffJSON(child, ev, rv, depth+1, out)
+			}
+			if len(*out) >= driftMaxDifferences {
+				return
+			}
+		}
+	case []any:
+		r, ok := running.([]any)
+		if !ok {
+			*out = append(*out, label+": type changed")
+			return
+		}
+		if len(e) != len(r) {
+			*out = append(*out, fmt.Sprintf("%s: length %d -> %d", label, len(e), len(r)))
+			return
+		}
+		for i := range e {
+			diffJSON(path+"."+strconv.Itoa(i), e[i], r[i], depth+1, out)
+		}
+	default:
+		*out = append(*out, label)
+	}
+}
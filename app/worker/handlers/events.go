@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	eventsRetryDefault = 5 * time.Second // 服务端没有指定时的重连间隔
+	eventsIdleTimeout  = 1 * time.Minute // 超过这个时间没有收到任何数据（包括保活）就认为连接已经失效
+)
+
+// listenEvents 保持与服务端的事件流连接，断开后按照退避间隔重连
+func (a *App) listenEvents(ctx context.Context) {
+	retry := eventsRetryDefault
+	delay := retry
+	for {
+		connected, err := a.streamEvents(ctx, &retry)
+		if ctx.Err() != nil {
+			a.l.Debug("stop event stream")
+			return
+		}
+		a.l.Warn("event stream disconnected, falling back to heartbeat polling", zap.Error(err))
+
+		// 连接成功过就重新开始退避，否则加倍，最长不超过心跳间隔
+		if connected {
+			delay = retry
+		} else if delay = delay * 2; delay > a.cfg.HeartbeatInterval {
+			delay = max(a.cfg.HeartbeatInterval, retry)
+		}
+
+		select {
+		case <-ctx.Done():
+			a.l.Debug("stop event stream")
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// streamEvents 建立一次事件流连接并处理直到断开，返回是否连接成功过
+func (a *App) streamEvents(ctx context.Context, retry *time.Duration) (bool, error) {
+	// 准备请求的基础信息
+	eventsPath := fmt.Sprintf("/api/worker/%d/events", a.cfg.InstanceID)
+	eventsReqUrl, err := url.JoinPath(a.cfg.ServerEndpoint, eventsPath)
+	if err != nil {
+		return false, fmt.Errorf("fail to join events request url: %w", err)
+	}
+
+	// 长时间没有数据时主动断开，避免卡在已经失效的连接上
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	idleTimer := time.AfterFunc(eventsIdleTimeout, cancel)
+	defer idleTimer.Stop()
+
+	eventsReq, err := http.NewRequestWithContext(streamCtx, "GET", eventsReqUrl, nil)
+	if err != nil {
+		return false, fmt.Errorf("fail to prepare events request: %w", err)
+	}
+	eventsReq.Header.Set("Authorization", "Bearer "+a.cfg.InstanceToken)
+	eventsReq.Header.Set("Accept", "text/event-stream")
+
+	// 发送请求
+	eventsRes, err := http.DefaultClient.Do(eventsReq)
+	if err != nil {
+		return false, fmt.Errorf("fail to send events request: %w", err)
+	}
+
+	defer eventsRes.Body.Close()
+
+	if eventsRes.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected events response status: %d", eventsRes.StatusCode)
+	}
+
+	// 断线期间可能错过了通知，连接后先同步一次
+	a.l.Debug("event stream connected")
+	a.requestSync()
+
+	// 按行解析 SSE ，空行表示一个事件结束
+	var eventType string
+	reader := bufio.NewReader(eventsRes.Body)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return true, fmt.Errorf("fail to read event stream: %w", err)
+		}
+		idleTimer.Reset(eventsIdleTimeout)
+
+		line = strings.TrimRight(line, "\r\n")
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch {
+		case line == "":
+			if eventType == "update" {
+				a.requestSync()
+			}
+			eventType = ""
+		case field == "":
+			// 注释（保活）
+		case field == "event":
+			eventType = value
+		case field == "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms > 0 {
+				*retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
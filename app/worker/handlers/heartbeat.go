@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"bytes"
 	"caddy-delivery-network/app/server/gen/oapi/worker"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,8 +13,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"path/filepath"
-	"time"
+	"strings"
 )
 
 func (a *App) heartbeat() {
@@ -42,11 +44,21 @@ func (a *App) heartbeat() {
 	hbRes, err := http.DefaultClient.Do(hbReq)
 	if err != nil {
 		a.l.Error("failed to send heartbeat request", zap.Any("req", hbReq), zap.Error(err))
+		a.fallbackToLastGood()
 		return
 	}
 
 	defer hbRes.Body.Close()
 
+	if hbRes.StatusCode != http.StatusOK {
+		a.l.Error("unexpected heartbeat response status", zap.Int("code", hbRes.StatusCode))
+		if hbRes.StatusCode >= http.StatusInternalServerError {
+			// 服务端出错，当作不可用
+			a.fallbackToLastGood()
+		}
+		return
+	}
+
 	// 解析请求体
 	var hbResBody worker.HeartbeatRes
 	err = json.NewDecoder(hbRes.Body).Decode(&hbResBody)
@@ -55,135 +67,349 @@ func (a *App) heartbeat() {
 		return
 	}
 
-	// 分析文件列表
+	// 同步，有变化、出错或启动后第一轮时上报结果
+	result := a.sync(&hbResBody)
+	if result.err == nil {
+		// 只有完整处理了清单时才记录并清理，避免误删
+		a.recordSync(result.files)
+
+		// 有变化时更新本地同步包
+		if result.changed || !a.lastGoodSaved {
+			if err := a.saveLastGood(); err != nil {
+				a.l.Error("failed to save last good bundle", zap.Error(err))
+			}
+		}
+	}
+	if result.changed || result.err != nil || !a.reported {
+		if err := a.sendReport(result); err != nil {
+			a.l.Error("failed to send report", zap.Error(err))
+		} else {
+			a.reported = true
+		}
+	}
+}
+
+type syncResult struct {
+	files        []worker.FileSyncResult
+	loadResponse string
+	diagnostics  []worker.ConfigDiagnostic
+	drift        *worker.DriftEvent
+	changed      bool
+	err          error
+}
+
+func (r *syncResult) fail(err error) *syncResult {
+	r.err = err
+	return r
+}
+
+func (a *App) sync(hbResBody *worker.HeartbeatRes) *syncResult {
+	result := &syncResult{}
+
+	// 准备暂存目录，所有变更先下载到这里，全部成功之后再替换
+	plan, err := a.newSyncPlan()
+	if err != nil {
+		a.l.Error("failed to prepare staging directory", zap.Error(err))
+		return result.fail(err)
+	}
+	defer plan.cleanup(a.l)
+
+	// 计算本地文件的摘要，依据内容摘要判断是否需要更新，不受时钟误差和文件修改时间的影响
+	local := make(map[string]string)
+	outdated := hbResBody.ConfigDigest != a.lastConfigDigest
 	for _, fileList := range hbResBody.FilesUpdatedAt {
-		if fileStat, err := os.Stat(fileList.Path); err != nil {
-			if errors.Is(err, os.ErrNotExist) {
-				// 需要创建文件，先创建目录，文件由之后非返回的条件统一创建
-				parentDir := filepath.Dir(fileList.Path)
-				if err := os.MkdirAll(parentDir, 0755); err != nil {
-					a.l.Error("failed to create parent directory", zap.String("path", parentDir), zap.Error(err))
-					continue
+		fPath, err := dataPath(a.cfg.DataRoot, fileList.Path)
+		if err != nil {
+			// 不允许服务端写入数据根目录之外的位置
+			a.l.Error("rejected file path", zap.String("path", fileList.Path), zap.Error(err))
+			result.files = append(result.files, fileSyncResult(fileList.Path, "", fileSyncFailed, err))
+			return result.fail(err)
+		}
+		localDigest, err := fileDigest(fPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			a.l.Error("failed to read file", zap.String("path", fileList.Path), zap.Error(err))
+			result.files = append(result.files, fileSyncResult(fileList.Path, "", fileSyncFailed, err))
+			return result.fail(err)
+		} else if err == nil {
+			local[fileList.Path] = localDigest
+
+			// 之前的版本写入的私钥可能允许其他用户读取
+			if isPrivateKey(fileList.Path) {
+				if err := os.Chmod(fPath, 0600); err != nil {
+					a.l.Error("failed to restrict private key permission", zap.String("path", fileList.Path), zap.Error(err))
 				}
-			} else {
-				a.l.Error("failed to stat file", zap.String("path", fileList.Path), zap.Error(err))
-				continue
 			}
-		} else if fileList.UpdatedAt <= fileStat.ModTime().Unix() {
+		}
+		if localDigest != fileList.Digest {
+			outdated = true
+		}
+	}
+	if !outdated {
+		// 没有需要更新的内容
+		for _, fileList := range hbResBody.FilesUpdatedAt {
+			result.files = append(result.files, fileSyncResult(fileList.Path, local[fileList.Path], fileSyncUnchanged, nil))
+		}
+		return result
+	}
+
+	// 优先通过同步包一次下载所有变更，失败时再逐个下载
+	var staged []int // 暂存的文件在 result.files 中的位置
+	if b, err := a.downloadBundle(local); err == nil {
+		staged, err = a.stageBundle(plan, b, local, result)
+		if err != nil {
+			return result.fail(err)
+		}
+	} else {
+		a.l.Warn("failed to download bundle, falling back to single downloads", zap.Error(err))
+		staged, err = a.stageSingle(plan, hbResBody, local, result)
+		if err != nil {
+			return result.fail(err)
+		}
+	}
+
+	if plan.isEmpty() {
+		return result
+	}
+	result.changed = true
+
+	// 替换文件并加载配置，失败时恢复到之前的版本
+	result.loadResponse, err = a.applySyncPlan(plan)
+	result.diagnostics = plan.diagnostics
+	if err != nil {
+		a.l.Error("failed to apply changes", zap.Error(err))
+		for _, i := range staged {
+			result.files[i].Status = fileSyncRolledBack
+			result.files[i].Digest = nil
+		}
+		return result.fail(err)
+	}
+
+	return result
+}
+
+// stageSingle 逐个下载需要更新的文件与配置并放进暂存目录，返回暂存的文件在 result.files 中的位置
+func (a *App) stageSingle(plan *syncPlan, hbResBody *worker.HeartbeatRes, local map[string]string, result *syncResult) ([]int, error) {
+	var staged []int
+	for _, fileList := range hbResBody.FilesUpdatedAt {
+		localDigest := local[fileList.Path]
+		if localDigest == fileList.Digest {
 			// 不需要更新文件，就继续处理下一个了
+			result.files = append(result.files, fileSyncResult(fileList.Path, localDigest, fileSyncUnchanged, nil))
 			continue
 		}
 
-		// 文件不存在或需要更新，则需要写入文件
-		if err := a.updateFile(fileList.Path); err != nil {
-			a.l.Error("failed to update file", zap.String("path", fileList.Path), zap.Error(err))
+		// 文件不存在或需要更新，先下载到暂存目录
+		content, err := a.downloadFile(fileList.Path, localDigest)
+		if err == nil && content != nil {
+			err = plan.stageFile(fileList.Path, content)
 		}
+		if err != nil {
+			// 放弃这一轮，保持正在使用的文件不变
+			a.l.Error("failed to download file", zap.String("path", fileList.Path), zap.Error(err))
+			result.files = append(result.files, fileSyncResult(fileList.Path, localDigest, fileSyncFailed, err))
+			return nil, err
+		} else if content == nil {
+			result.files = append(result.files, fileSyncResult(fileList.Path, localDigest, fileSyncUnchanged, nil))
+			continue
+		}
+
+		staged = append(staged, len(result.files))
+		result.files = append(result.files, fileSyncResult(fileList.Path, fileList.Digest, fileSyncUpdated, nil))
 	}
 
 	// 分析配置是否发生更新
-	if hbResBody.ConfigUpdatedAt > a.lastConfigUpdate {
-		if err := a.updateConfig(); err != nil {
-			a.l.Error("failed to update config", zap.Error(err))
-		} else {
-			a.lastConfigUpdate = time.Now().Unix() // 使用当前时间戳作为配置更新时间
+	if hbResBody.ConfigDigest != a.lastConfigDigest {
+		config, digest, err := a.downloadConfig()
+		if err != nil {
+			a.l.Error("failed to download config", zap.Error(err))
+			return nil, err
+		}
+		if config != nil {
+			plan.stageConfig(config, digest)
 		}
 	}
+
+	return staged, nil
+}
+
+// fileDigest 计算本地文件的 SHA-256 摘要，与服务端的格式一致
+func fileDigest(fPath string) (string, error) {
+	f, err := os.Open(fPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
 }
 
-func (a *App) updateFile(fPath string) error {
+// checkDigest 检查下载的内容与响应的 ETag 是否一致，避免写入不完整的内容
+func checkDigest(content []byte, etag string) (string, error) {
+	sum := sha256.Sum256(content)
+	digest := hex.EncodeToString(sum[:])
+	if etag != "" && strings.Trim(strings.TrimPrefix(etag, "W/"), `"`) != digest {
+		return "", fmt.Errorf("digest mismatch: expected %s, got %s", etag, digest)
+	}
+	return digest, nil
+}
+
+// downloadFile 下载文件内容，本地已经是最新版本时返回 nil
+func (a *App) downloadFile(fPath string, localDigest string) ([]byte, error) {
 	// 请求文件数据
 	filePath := fmt.Sprintf("/api/worker/%d/file", a.cfg.InstanceID)
 	fileReqUrl, err := url.JoinPath(a.cfg.ServerEndpoint, filePath)
 	if err != nil {
 		a.l.Error("failed to join file request url", zap.String("server", a.cfg.ServerEndpoint), zap.String("path", filePath), zap.Error(err))
-		return fmt.Errorf("fail to join file request url: %w", err)
+		return nil, fmt.Errorf("fail to join file request url: %w", err)
 	}
 	fileReq, err := http.NewRequest("GET", fileReqUrl, nil)
 	if err != nil {
 		a.l.Error("failed to prepare file request", zap.String("url", fileReqUrl), zap.Error(err))
-		return fmt.Errorf("fail to prepare file request: %w", err)
+		return nil, fmt.Errorf("fail to prepare file request: %w", err)
 	}
 	fileReq.Header.Set("Authorization", "Bearer "+a.cfg.InstanceToken)
-	fileReq.Header.Set("X-File-Path", filePath)
+	fileReq.Header.Set("X-File-Path", fPath)
+	if localDigest != "" {
+		fileReq.Header.Set("If-None-Match", `"`+localDigest+`"`)
+	}
 
 	// 发送请求
 	fileRes, err := http.DefaultClient.Do(fileReq)
 	if err != nil {
 		a.l.Error("failed to send file request", zap.Any("req", fileReq), zap.Error(err))
-		return fmt.Errorf("fail to send file request: %w", err)
+		return nil, fmt.Errorf("fail to send file request: %w", err)
 	}
 
 	defer fileRes.Body.Close()
 
-	// 打开文件
-	f, err := os.OpenFile(fPath, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		a.l.Error("failed to open file", zap.String("path", fPath), zap.Error(err))
-		return fmt.Errorf("fail to open file: %w", err)
+	switch fileRes.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		// 心跳之后文件又变回了本地的版本
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unexpected file response status: %d", fileRes.StatusCode)
 	}
 
-	defer f.Close()
-
-	// 写出数据
-	if _, err := io.Copy(f, fileRes.Body); err != nil {
-		a.l.Error("failed to copy file response", zap.Any("resp", fileReq), zap.Error(err))
-		return fmt.Errorf("fail to copy file response: %w", err)
+	// 完整读取并校验
+	content, err := io.ReadAll(fileRes.Body)
+	if err != nil {
+		a.l.Error("failed to read file response", zap.String("path", fPath), zap.Error(err))
+		return nil, fmt.Errorf("fail to read file response: %w", err)
+	}
+	if _, err := checkDigest(content, fileRes.Header.Get("ETag")); err != nil {
+		a.l.Error("failed to verify file", zap.String("path", fPath), zap.Error(err))
+		return nil, fmt.Errorf("fail to verify file: %w", err)
 	}
 
-	return nil
+	return content, nil
 }
 
-func (a *App) updateConfig() error {
+// downloadConfig 下载配置与它的摘要，与已经加载的配置相同时返回 nil
+func (a *App) downloadConfig() ([]byte, string, error) {
 	// 请求配置数据
 	configPath := fmt.Sprintf("/api/worker/%d/config", a.cfg.InstanceID)
 	configReqUrl, err := url.JoinPath(a.cfg.ServerEndpoint, configPath)
 	if err != nil {
 		a.l.Error("failed to join config request url", zap.String("server", a.cfg.ServerEndpoint), zap.String("path", configPath), zap.Error(err))
-		return fmt.Errorf("fail to join config request url: %w", err)
+		return nil, "", fmt.Errorf("fail to join config request url: %w", err)
 	}
 	configReq, err := http.NewRequest("GET", configReqUrl, nil)
 	if err != nil {
 		a.l.Error("failed to prepare config request", zap.String("url", configReqUrl), zap.Error(err))
-		return fmt.Errorf("fail to prepare config request: %w", err)
+		return nil, "", fmt.Errorf("fail to prepare config request: %w", err)
 	}
 	configReq.Header.Set("Authorization", "Bearer "+a.cfg.InstanceToken)
+	if a.lastConfigDigest != "" {
+		configReq.Header.Set("If-None-Match", `"`+a.lastConfigDigest+`"`)
+	}
 
 	// 发送请求
 	configRes, err := http.DefaultClient.Do(configReq)
 	if err != nil {
 		a.l.Error("failed to send config request", zap.Any("req", configReq), zap.Error(err))
-		return fmt.Errorf("fail to send config request: %w", err)
+		return nil, "", fmt.Errorf("fail to send config request: %w", err)
 	}
 
 	defer configRes.Body.Close()
 
+	switch configRes.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		// 心跳之后配置又变回了已经加载的版本
+		return nil, "", nil
+	default:
+		return nil, "", fmt.Errorf("unexpected config response status: %d", configRes.StatusCode)
+	}
+
+	configBytes, err := io.ReadAll(configRes.Body)
+	if err != nil {
+		a.l.Error("failed to read config response", zap.Error(err))
+		return nil, "", fmt.Errorf("fail to read config response: %w", err)
+	}
+	digest, err := checkDigest(configBytes, configRes.Header.Get("ETag"))
+	if err != nil {
+		a.l.Error("failed to verify config", zap.Error(err))
+		return nil, "", fmt.Errorf("fail to verify config: %w", err)
+	}
+
+	return configBytes, digest, nil
+}
+
+// loadConfig 把配置加载到 Caddy 并返回响应内容， force 时即使配置没有变化也要求 Caddy 重新加载（例如证书文件发生了变化）
+func (a *App) loadConfig(config []byte, force bool) (string, error) {
 	// 准备配置更新请求
 	caddyConfigUpdateReqUrl, err := url.JoinPath(a.cfg.CaddyEndpoint, "/load")
 	if err != nil {
 		a.l.Error("failed to prepare caddy config update url", zap.Error(err))
-		return fmt.Errorf("fail to prepare caddy config update url: %w", err)
+		return "", fmt.Errorf("fail to prepare caddy config update url: %w", err)
+	}
+	body, contentType := a.renderConfig(config), "text/caddyfile"
+	if a.caddy != nil {
+		// 看管 Caddy 时先适配再固定管理接口的地址，加载 JSON 配置
+		adapted, _, err := a.adaptConfig(config)
+		if err == nil {
+			adapted, err = a.pinAdminListen(adapted)
+		}
+		if err != nil {
+			a.l.Error("failed to adapt caddy config", zap.Error(err))
+			return "", fmt.Errorf("fail to adapt caddy config: %w", err)
+		}
+		body, contentType = adapted, "application/json"
 	}
-	caddyConfigUpdateReq, err := http.NewRequest("POST", caddyConfigUpdateReqUrl, configReq.Body)
+	caddyConfigUpdateReq, err := http.NewRequest("POST", caddyConfigUpdateReqUrl, bytes.NewReader(body))
 	if err != nil {
 		a.l.Error("failed to prepare caddy config update url", zap.Error(err))
-		return fmt.Errorf("fail to prepare caddy config update url: %w", err)
+		return "", fmt.Errorf("fail to prepare caddy config update url: %w", err)
+	}
+	caddyConfigUpdateReq.Header.Set("Content-Type", contentType)
+	if force {
+		caddyConfigUpdateReq.Header.Set("Cache-Control", "must-revalidate")
 	}
-	caddyConfigUpdateReq.Header.Set("Content-Type", "text/caddyfile")
 
 	// 发送请求
 	caddyConfigUpdateRes, err := http.DefaultClient.Do(caddyConfigUpdateReq)
 	if err != nil {
 		a.l.Error("failed to send caddy config update request", zap.Any("req", caddyConfigUpdateReq), zap.Error(err))
-		return fmt.Errorf("fail to send caddy config update request: %w", err)
+		return "", fmt.Errorf("fail to send caddy config update request: %w", err)
 	}
 
 	defer caddyConfigUpdateRes.Body.Close()
 
+	resBody, err := io.ReadAll(caddyConfigUpdateRes.Body)
+	if err != nil {
+		a.l.Error("failed to read caddy config update response", zap.Error(err))
+		return "", fmt.Errorf("fail to read caddy config update response: %w", err)
+	}
 	if caddyConfigUpdateRes.StatusCode != http.StatusOK {
-		a.l.Error("failed to update caddy config", zap.Int("code", caddyConfigUpdateRes.StatusCode), zap.Any("res", caddyConfigUpdateRes), zap.Error(err))
-		return fmt.Errorf("failed to update caddy config")
+		a.l.Error("failed to update caddy config", zap.Int("code", caddyConfigUpdateRes.StatusCode), zap.ByteString("body", resBody))
+		return string(resBody), fmt.Errorf("caddy rejected config (%d): %s", caddyConfigUpdateRes.StatusCode, strings.TrimSpace(string(resBody)))
 	}
 
 	// 返回
-	return nil
+	return string(resBody), nil
 }
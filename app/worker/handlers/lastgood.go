@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"archive/tar"
+	"caddy-delivery-network/app/server/gen/oapi/worker"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"os"
+	"sort"
+	"time"
+)
+
+// saveLastGood 把已经成功应用的配置和所有文件保存为本地同步包，服务端不可用时用它恢复
+func (a *App) saveLastGood() error {
+	if a.lastConfig == nil {
+		return nil
+	}
+
+	manifest := worker.BundleManifest{
+		ConfigDigest:   a.lastConfigDigest,
+		ConfigIncluded: true,
+		Files:          []worker.BundleFile{},
+	}
+
+	// 只保存仍在清单中的文件，内容必须与记录的摘要一致
+	var paths []string
+	for relPath, mf := range a.state.Files {
+		if mf.OrphanedAt == 0 {
+			paths = append(paths, relPath)
+		}
+	}
+	sort.Strings(paths)
+	contents := make([][]byte, 0, len(paths))
+	for i, relPath := range paths {
+		fPath, err := dataPath(a.cfg.DataRoot, relPath)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(fPath)
+		if err != nil {
+			return fmt.Errorf("fail to read %s: %w", relPath, err)
+		}
+		digest, err := checkDigest(content, a.state.Files[relPath].Digest)
+		if err != nil {
+			return fmt.Errorf("fail to verify %s: %w", relPath, err)
+		}
+
+		entry := fmt.Sprintf("files/%d", i)
+		manifest.Files = append(manifest.Files, worker.BundleFile{
+			Path:   relPath,
+			Digest: digest,
+			Entry:  &entry,
+		})
+		contents = append(contents, content)
+	}
+
+	manifestBytes, err := json.Marshal(&manifest)
+	if err != nil {
+		return fmt.Errorf("fail to marshal manifest: %w", err)
+	}
+
+	// 包含私钥，只允许所有者读取；先写入临时文件再替换
+	tmp := a.cfg.LastGoodBundle + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("fail to create last good bundle: %w", err)
+	}
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	modTime := time.Now()
+	write := func(name string, content []byte) error {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content)), ModTime: modTime}); err != nil {
+			return err
+		}
+		_, err := tw.Write(content)
+		return err
+	}
+
+	err = write(bundleManifestName, manifestBytes)
+	if err == nil {
+		err = write(bundleConfigName, a.lastConfig)
+	}
+	for i := 0; err == nil && i < len(contents); i++ {
+		err = write(*manifest.Files[i].Entry, contents[i])
+	}
+	if err == nil {
+		err = tw.Close()
+	}
+	if err == nil {
+		err = gw.Close()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("fail to write last good bundle: %w", err)
+	}
+	if err := os.Rename(tmp, a.cfg.LastGoodBundle); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("fail to replace last good bundle: %w", err)
+	}
+
+	a.lastGoodSaved = true
+	a.l.Debug("saved last good bundle", zap.Int("files", len(paths)), zap.String("configDigest", a.lastConfigDigest))
+	return nil
+}
+
+// applyLastGood 从本地同步包恢复文件并把配置加载到 Caddy ，不需要联系服务端；调用方需要持有锁
+func (a *App) applyLastGood() (string, error) {
+	f, err := os.Open(a.cfg.LastGoodBundle)
+	if err != nil {
+		return "", fmt.Errorf("fail to open last good bundle: %w", err)
+	}
+	defer f.Close()
+
+	b, err := readBundle(f)
+	if err != nil {
+		return "", err
+	}
+	if err := b.verify(); err != nil {
+		return "", err
+	}
+
+	plan, err := a.newSyncPlan()
+	if err != nil {
+		return "", err
+	}
+	defer plan.cleanup(a.l)
+
+	// 只恢复缺失或被修改的文件
+	for _, bf := range b.manifest.Files {
+		if bf.Entry == nil {
+			continue
+		}
+		fPath, err := dataPath(a.cfg.DataRoot, bf.Path)
+		if err != nil {
+			return "", err
+		}
+		if localDigest, err := fileDigest(fPath); err == nil && localDigest == bf.Digest {
+			continue
+		} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("fail to read %s: %w", bf.Path, err)
+		}
+		a.l.Info("restoring file from last good bundle", zap.String("path", bf.Path))
+		if err := plan.stageFile(bf.Path, b.entries[*bf.Entry]); err != nil {
+			return "", err
+		}
+	}
+	plan.stageConfig(b.config, b.manifest.ConfigDigest)
+
+	loadResponse, err := a.applySyncPlan(plan)
+	if err != nil {
+		return loadResponse, err
+	}
+
+	a.saveAppliedState()
+	a.l.Info("last good bundle applied", zap.Int("restored", len(plan.files)), zap.String("configDigest", b.manifest.ConfigDigest))
+	return loadResponse, nil
+}
+
+// reapply Caddy 丢失了配置（例如重启）时重新应用，优先使用本地同步包以便同时恢复文件；调用方需要持有锁
+func (a *App) reapply() (string, error) {
+	loadResponse, err := a.applyLastGood()
+	if err == nil {
+		return loadResponse, nil
+	}
+	if a.lastConfig == nil {
+		return loadResponse, err
+	}
+
+	a.l.Warn("failed to apply last good bundle, reloading last config", zap.Error(err))
+	return a.loadConfig(a.lastConfig, true)
+}
+
+// fallbackToLastGood 服务端不可用时检查 Caddy 是否还有配置，没有的话使用本地同步包；调用方需要持有锁
+func (a *App) fallbackToLastGood() {
+	if _, err := os.Stat(a.cfg.LastGoodBundle); err != nil && a.lastConfig == nil {
+		// 从来没有成功同步过，没有可以恢复的内容
+		return
+	}
+
+	running, err := a.runningConfig()
+	if err != nil {
+		a.l.Error("failed to get running caddy config", zap.Error(err))
+		return
+	}
+	if !isEmptyCaddyConfig(running) {
+		return
+	}
+
+	a.l.Warn("server unreachable and caddy has no config, applying last good bundle")
+	if _, err := a.reapply(); err != nil {
+		a.l.Error("failed to apply last good bundle", zap.Error(err))
+	}
+}
+
+// isEmptyCaddyConfig 判断是否是 Caddy 刚启动时的空配置（或只有管理接口的引导配置）
+func isEmptyCaddyConfig(normalized []byte) bool {
+	var config map[string]json.RawMessage
+	if err := json.Unmarshal(normalized, &config); err != nil {
+		return false
+	}
+	for key := range config {
+		if key != "admin" {
+			return false
+		}
+	}
+	return true
+}
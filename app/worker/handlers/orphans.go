@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"caddy-delivery-network/app/server/gen/oapi/worker"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// 证书私钥的文件名，与服务端保持一致
+const privateKeyName = "key.pem"
+
+func isPrivateKey(relPath string) bool {
+	return filepath.Base(relPath) == privateKeyName
+}
+
+// removeOrphans 根据这一轮同步的清单更新管理的文件列表，删除从清单中消失超过宽限期的文件，
+// 只处理 worker 自己写入过的文件，数据根目录中的其他文件不受影响；由调用方保存状态
+func (a *App) removeOrphans(files []worker.FileSyncResult) {
+	now := time.Now()
+
+	// 记录清单中的文件
+	current := make(map[string]struct{}, len(files))
+	for _, f := range files {
+		current[f.Path] = struct{}{}
+
+		mf, ok := a.state.Files[f.Path]
+		if !ok {
+			mf = &managedFile{}
+			a.state.Files[f.Path] = mf
+		}
+		if mf.OrphanedAt != 0 {
+			a.l.Info("file is back in manifest, cancel removal", zap.String("path", f.Path))
+			mf.OrphanedAt = 0
+		}
+		if f.Digest != nil {
+			mf.Digest = *f.Digest
+		}
+	}
+
+	// 找出宽限期已过的文件
+	var expired []string
+	for relPath, mf := range a.state.Files {
+		if _, ok := current[relPath]; ok {
+			continue
+		}
+		if mf.OrphanedAt == 0 {
+			a.l.Info("file dropped out of manifest, scheduled for removal", zap.String("path", relPath), zap.Duration("grace", a.cfg.OrphanGracePeriod))
+			mf.OrphanedAt = now.Unix()
+		}
+		if now.Sub(time.Unix(mf.OrphanedAt, 0)) >= a.cfg.OrphanGracePeriod {
+			expired = append(expired, relPath)
+		}
+	}
+
+	// 私钥优先删除，避免中途出错时私钥残留
+	sort.Slice(expired, func(i, j int) bool {
+		if ki, kj := isPrivateKey(expired[i]), isPrivateKey(expired[j]); ki != kj {
+			return ki
+		}
+		return expired[i] < expired[j]
+	})
+	for _, relPath := range expired {
+		if err := a.removeOrphan(relPath); err != nil {
+			// 保留记录，下一轮再试
+			a.l.Error("failed to remove orphaned file", zap.String("path", relPath), zap.Error(err))
+			continue
+		}
+		delete(a.state.Files, relPath)
+	}
+}
+
+func (a *App) removeOrphan(relPath string) error {
+	fPath, err := dataPath(a.cfg.DataRoot, relPath)
+	if err != nil {
+		// 不应该出现，记录下来的路径都经过检查
+		a.l.Warn("forgetting invalid managed path", zap.String("path", relPath), zap.Error(err))
+		return nil
+	}
+
+	if isPrivateKey(relPath) {
+		err = shredFile(fPath)
+	} else {
+		err = os.Remove(fPath)
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		a.l.Info("orphaned file already removed", zap.String("path", relPath))
+		return nil
+	} else if err != nil {
+		return err
+	}
+	a.l.Info("removed orphaned file", zap.String("path", relPath), zap.Bool("privateKey", isPrivateKey(relPath)))
+
+	// 清理空的上级目录（例如整张证书的目录），不会删除数据根目录本身
+	for dir := filepath.Dir(fPath); dir != a.cfg.DataRoot && dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			break
+		}
+	}
+
+	return nil
+}
+
+// shredFile 先用零覆盖内容并落盘再删除；在写时复制的文件系统或 SSD 上只能尽力而为
+func shredFile(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(make([]byte, info.Size())); err != nil {
+		f.Close()
+		return fmt.Errorf("fail to overwrite: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("fail to sync: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bytes"
+	"caddy-delivery-network/app/server/gen/oapi/worker"
+	"caddy-delivery-network/app/worker/config"
+	"encoding/json"
+	"fmt"
+	"go.uber.org/zap"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	fileSyncUnchanged  = "unchanged"
+	fileSyncUpdated    = "updated"
+	fileSyncFailed     = "failed"
+	fileSyncRolledBack = "rolled_back"
+)
+
+func fileSyncResult(path string, digest string, status string, err error) worker.FileSyncResult {
+	res := worker.FileSyncResult{
+		Path:   path,
+		Status: status,
+	}
+	if digest != "" {
+		res.Digest = &digest
+	}
+	if err != nil {
+		errMsg := err.Error()
+		res.Error = &errMsg
+	}
+	return res
+}
+
+// caddyVersion 通过 Caddy 可执行文件获取版本，找不到时返回空字符串
+func (a *App) caddyVersion() string {
+	if a.cfg.CaddyBinary == "" {
+		return ""
+	}
+
+	out, err := exec.Command(a.cfg.CaddyBinary, "version").Output()
+	if err != nil {
+		a.l.Debug("failed to get caddy version", zap.String("binary", a.cfg.CaddyBinary), zap.Error(err))
+		return ""
+	}
+
+	version, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return version
+}
+
+// sendReport 把这一轮的同步结果上报给服务端
+func (a *App) sendReport(result *syncResult) error {
+	syncedAt := time.Now().Unix()
+	report := worker.SyncReport{
+		Status:        "ok",
+		ConfigDigest:  a.lastConfigDigest,
+		SyncedAt:      &syncedAt,
+		WorkerVersion: &config.Version,
+	}
+	if result.err != nil {
+		errMsg := result.err.Error()
+		report.Status = "failed"
+		report.Error = &errMsg
+	}
+	if result.files != nil {
+		report.Files = &result.files
+	}
+	if result.loadResponse != "" {
+		report.CaddyLoadResponse = &result.loadResponse
+	}
+	if len(result.diagnostics) > 0 {
+		report.Diagnostics = &result.diagnostics
+	}
+	if result.drift != nil {
+		report.Drift = result.drift
+	}
+	if version := a.caddyVersion(); version != "" {
+		report.CaddyVersion = &version
+	}
+
+	reportBytes, err := json.Marshal(&report)
+	if err != nil {
+		return fmt.Errorf("fail to marshal report: %w", err)
+	}
+
+	// 准备请求的基础信息
+	reportPath := fmt.Sprintf("/api/worker/%d/report", a.cfg.InstanceID)
+	reportReqUrl, err := url.JoinPath(a.cfg.ServerEndpoint, reportPath)
+	if err != nil {
+		return fmt.Errorf("fail to join report request url: %w", err)
+	}
+	reportReq, err := http.NewRequest("POST", reportReqUrl, bytes.NewReader(reportBytes))
+	if err != nil {
+		return fmt.Errorf("fail to prepare report request: %w", err)
+	}
+	reportReq.Header.Set("Authorization", "Bearer "+a.cfg.InstanceToken)
+	reportReq.Header.Set("Content-Type", "application/json")
+
+	// 发送请求
+	reportRes, err := http.DefaultClient.Do(reportReq)
+	if err != nil {
+		return fmt.Errorf("fail to send report request: %w", err)
+	}
+
+	defer reportRes.Body.Close()
+
+	if reportRes.StatusCode != http.StatusNoContent && reportRes.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected report response status: %d", reportRes.StatusCode)
+	}
+
+	return nil
+}
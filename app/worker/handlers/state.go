@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"bytes"
+	"caddy-delivery-network/app/server/gen/oapi/worker"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// managedFile 由 worker 写入并管理的文件
+type managedFile struct {
+	Digest     string `json:"digest"`
+	OrphanedAt int64  `json:"orphaned_at,omitempty"` // 从清单中消失的时间，仍在清单中时为 0
+}
+
+// workerState 保存在本地的状态，重启后继续使用
+type workerState struct {
+	Files          map[string]*managedFile `json:"files"`                     // 键是服务端下发的相对路径
+	Config         string                  `json:"config,omitempty"`          // 已经加载到 Caddy 的配置
+	ConfigDigest   string                  `json:"config_digest,omitempty"`   // 服务端下发的配置的摘要
+	ExpectedConfig json.RawMessage         `json:"expected_config,omitempty"` // 配置适配后规范化的 JSON ，用于检查配置漂移
+	LastSyncAt     int64                   `json:"last_sync_at,omitempty"`    // 最近一次成功同步的时间
+}
+
+func newWorkerState() *workerState {
+	return &workerState{
+		Files: make(map[string]*managedFile),
+	}
+}
+
+// loadState 读取本地状态，文件不存在或损坏时从空状态开始
+func (a *App) loadState() {
+	a.state = newWorkerState()
+
+	content, err := os.ReadFile(a.cfg.StateFile)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			a.l.Error("failed to read state file", zap.String("path", a.cfg.StateFile), zap.Error(err))
+		}
+		return
+	}
+
+	state := newWorkerState()
+	if err := json.Unmarshal(content, state); err != nil {
+		a.l.Error("failed to decode state file, starting from empty state", zap.String("path", a.cfg.StateFile), zap.Error(err))
+		return
+	}
+	if state.Files == nil {
+		state.Files = make(map[string]*managedFile)
+	}
+	a.state = state
+
+	// 恢复已经应用的配置，服务端没有变化时就不需要重新加载（启动后会先确认 Caddy 正在运行这份配置）
+	if state.Config != "" && state.ConfigDigest != "" {
+		a.lastConfig = []byte(state.Config)
+		a.lastConfigDigest = state.ConfigDigest
+		if len(state.ExpectedConfig) > 0 {
+			a.expectedConfig = state.ExpectedConfig
+		}
+		a.l.Info("restored applied config from state", zap.String("digest", state.ConfigDigest), zap.Int64("lastSyncAt", state.LastSyncAt))
+	}
+}
+
+// checkRestoredConfig 确认 Caddy 正在运行从状态中恢复的配置（例如主机重启后 Caddy 只有空配置或本地的 Caddyfile ），
+// 不一致时重新应用；无法恢复时清除配置摘要，让下一次同步从服务端重新加载
+func (a *App) checkRestoredConfig() {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if a.lastConfigDigest == "" {
+		// 没有恢复配置，同步时会从服务端加载
+		return
+	}
+
+	if a.expectedConfig != nil {
+		running, err := a.runningConfig()
+		if err != nil {
+			a.l.Error("failed to get running caddy config", zap.Error(err))
+		} else if bytes.Equal(running, a.expectedConfig) {
+			return
+		}
+	}
+
+	a.l.Info("running caddy config does not match restored state, reapplying")
+	if _, err := a.reapply(); err != nil {
+		a.l.Error("failed to reapply restored config, reloading from server", zap.Error(err))
+		a.lastConfigDigest = ""
+	}
+}
+
+// recordSync 记录一轮成功的同步：更新管理的文件、已经应用的配置与同步时间，然后保存
+func (a *App) recordSync(files []worker.FileSyncResult) {
+	a.removeOrphans(files)
+	a.state.LastSyncAt = time.Now().Unix()
+	a.saveAppliedState()
+}
+
+// saveAppliedState 记录已经应用的配置并保存
+func (a *App) saveAppliedState() {
+	a.state.Config = string(a.lastConfig)
+	a.state.ConfigDigest = a.lastConfigDigest
+	a.state.ExpectedConfig = a.expectedConfig
+
+	if err := a.saveState(); err != nil {
+		a.l.Error("failed to save state", zap.Error(err))
+	}
+}
+
+// saveState 原子地写入本地状态
+func (a *App) saveState() error {
+	content, err := json.Marshal(a.state)
+	if err != nil {
+		return fmt.Errorf("fail to encode state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(a.cfg.StateFile), 0700); err != nil {
+		return fmt.Errorf("fail to create state directory: %w", err)
+	}
+	tmp := a.cfg.StateFile + ".tmp"
+	if err := writeFileSync(tmp, content, 0644); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("fail to write state: %w", err)
+	}
+	if err := os.Rename(tmp, a.cfg.StateFile); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("fail to replace state: %w", err)
+	}
+	return nil
+}
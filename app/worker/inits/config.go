@@ -4,6 +4,7 @@ import (
 	"caddy-delivery-network/app/worker/config"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -44,11 +45,88 @@ func Config() (*config.Config, error) {
 		cfg.HeartbeatInterval = interval
 	}
 
+	if dataRoot, exist := os.LookupEnv("DATA_ROOT"); !exist {
+		cfg.DataRoot = "/data/cdn"
+	} else if !filepath.IsAbs(dataRoot) {
+		return nil, fmt.Errorf("DATA_ROOT should be an absolute path")
+	} else {
+		cfg.DataRoot = filepath.Clean(dataRoot)
+	}
+
+	if stagingDir, exist := os.LookupEnv("STAGING_DIR"); !exist {
+		cfg.StagingDir = filepath.Join(cfg.DataRoot, ".staging")
+	} else {
+		cfg.StagingDir = stagingDir
+	}
+
+	if stateFile, exist := os.LookupEnv("STATE_FILE"); !exist {
+		cfg.StateFile = filepath.Join(cfg.DataRoot, ".worker-state.json")
+	} else {
+		cfg.StateFile = stateFile
+	}
+
+	if lastGood, exist := os.LookupEnv("LAST_GOOD_BUNDLE"); !exist {
+		cfg.LastGoodBundle = filepath.Join(cfg.DataRoot, ".last-good.tar.gz")
+	} else {
+		cfg.LastGoodBundle = lastGood
+	}
+
+	// 服务端下发的文件可能会覆盖数据根目录中的其他文件，只有以 . 开头的路径保留给 worker
+	for name, path := range map[string]string{
+		"STAGING_DIR":      cfg.StagingDir,
+		"STATE_FILE":       cfg.StateFile,
+		"LAST_GOOD_BUNDLE": cfg.LastGoodBundle,
+	} {
+		if rel, err := filepath.Rel(cfg.DataRoot, path); err == nil && filepath.IsLocal(rel) {
+			if first, _, _ := strings.Cut(filepath.ToSlash(rel), "/"); !strings.HasPrefix(first, ".") {
+				return nil, fmt.Errorf("%s inside DATA_ROOT should be under a path starting with \".\"", name)
+			}
+		}
+	}
+
+	if gracePeriodStr, exist := os.LookupEnv("ORPHAN_GRACE_PERIOD"); !exist {
+		cfg.OrphanGracePeriod = 24 * time.Hour // 默认保留一天，方便回滚
+	} else if gracePeriod, err := time.ParseDuration(gracePeriodStr); err != nil || gracePeriod < 0 {
+		return nil, fmt.Errorf("ORPHAN_GRACE_PERIOD should be a valid non-negative duration")
+	} else {
+		cfg.OrphanGracePeriod = gracePeriod
+	}
+
 	if caddyEp, exist := os.LookupEnv("CADDY_ENDPOINT"); !exist {
 		return nil, fmt.Errorf("CADDY_ENDPOINT environment variable not set")
 	} else {
 		cfg.CaddyEndpoint = caddyEp
 	}
 
+	if caddyBin, exist := os.LookupEnv("CADDY_BINARY"); !exist {
+		cfg.CaddyBinary = "caddy" // 默认从 PATH 中查找
+	} else {
+		cfg.CaddyBinary = caddyBin
+	}
+
+	if driftIntervalStr, exist := os.LookupEnv("DRIFT_CHECK_INTERVAL"); !exist {
+		cfg.DriftCheckInterval = 5 * time.Minute // 默认每五分钟检查一次
+	} else if interval, err := time.ParseDuration(driftIntervalStr); err != nil || interval < 0 {
+		return nil, fmt.Errorf("DRIFT_CHECK_INTERVAL should be a valid non-negative duration")
+	} else {
+		cfg.DriftCheckInterval = interval
+	}
+
+	if superviseStr, exist := os.LookupEnv("CADDY_SUPERVISE"); exist {
+		if supervise, err := strconv.ParseBool(superviseStr); err != nil {
+			return nil, fmt.Errorf("CADDY_SUPERVISE should be a boolean")
+		} else {
+			cfg.SuperviseCaddy = supervise
+		}
+	}
+
+	if stopTimeoutStr, exist := os.LookupEnv("CADDY_STOP_TIMEOUT"); !exist {
+		cfg.CaddyStopTimeout = 30 * time.Second
+	} else if timeout, err := time.ParseDuration(stopTimeoutStr); err != nil {
+		return nil, fmt.Errorf("CADDY_STOP_TIMEOUT should be a valid duration")
+	} else {
+		cfg.CaddyStopTimeout = timeout
+	}
+
 	return &cfg, nil
 }
@@ -4,7 +4,11 @@ import (
 	"caddy-delivery-network/app/worker/handlers"
 	"caddy-delivery-network/app/worker/inits"
 	"fmt"
+	"go.uber.org/zap"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
@@ -27,6 +31,17 @@ func main() {
 	handlerApp := handlers.NewApp(cfg, l)
 	handlerApp.Start()
 
-	// 卡住进程避免结束
-	select {}
+	// 等待退出信号； SIGHUP 时重启看管的 Caddy （例如升级了可执行文件）
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	for sig := range sigChan {
+		if sig == syscall.SIGHUP {
+			handlerApp.RestartCaddy()
+			continue
+		}
+
+		l.Info("stopping", zap.String("signal", sig.String()))
+		handlerApp.Stop()
+		return
+	}
 }